@@ -0,0 +1,14 @@
+package database
+
+import (
+	"github.com/faetools/client"
+	"github.com/faetools/go-notion-codegen/transport"
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// NewClient returns a notion client that keeps to Notion's rate limit
+// and retries requests that failed temporarily.
+func NewClient(bearer string, opts ...client.Option) (*notion.Client, error) {
+	// copy the options so that the caller's slice is not written to
+	return notion.NewDefaultClient(bearer, append(append([]client.Option{}, opts...), transport.WithRateLimit())...)
+}
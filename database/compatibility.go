@@ -0,0 +1,216 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// IncompatibleError is returned if a database is not compatible with the schema code was generated from.
+type IncompatibleError struct {
+	// ID is the ID of the database.
+	ID notion.Id
+	// Problems are the differences that make the database incompatible, sorted.
+	Problems []string
+}
+
+func (e *IncompatibleError) Error() string {
+	return fmt.Sprintf("database %s is incompatible: %s", e.ID, strings.Join(e.Problems, "; "))
+}
+
+type compatibilityOptions struct {
+	byID             bool
+	statuses         map[string]StatusConfig
+	databaseStatuses map[string]StatusConfig
+}
+
+// CompatibilityOption sets an option for checking the compatibility of a database.
+type CompatibilityOption func(*compatibilityOptions)
+
+// MatchByID makes properties be found by their IDs before their names,
+// so that renamed properties are compatible.
+func MatchByID() CompatibilityOption {
+	return func(o *compatibilityOptions) { o.byID = true }
+}
+
+// StatusOptions makes the options of statuses be checked like those of selects.
+// notion.PropertyMeta can't hold them, so they are given by the name of their property.
+func StatusOptions(statuses map[string]StatusConfig) CompatibilityOption {
+	return func(o *compatibilityOptions) { o.statuses = statuses }
+}
+
+// DatabaseStatuses gives Compatible the options of the statuses of the database by the name of their property,
+// which notion.PropertyMeta can't hold. CheckCompatibility loads them with the database.
+func DatabaseStatuses(statuses map[string]StatusConfig) CompatibilityOption {
+	return func(o *compatibilityOptions) { o.databaseStatuses = statuses }
+}
+
+// CheckCompatibility loads the database and returns an *IncompatibleError
+// if it is not compatible with the schema, see Compatible.
+func CheckCompatibility(ctx context.Context, cli *notion.Client, id notion.Id, schema notion.PropertyMetaMap,
+	opts ...CompatibilityOption,
+) error {
+	b, err := do(ctx, cli, http.MethodGet, "./v1/databases/"+url.PathEscape(string(id)), nil)
+	if err != nil {
+		return fmt.Errorf("getting database %s: %w", id, err)
+	}
+
+	db := notion.Database{}
+	if err := json.Unmarshal(b, &db); err != nil {
+		return fmt.Errorf("decoding database %s: %w", id, err)
+	}
+
+	// notion.Database can't hold the options of statuses
+	raw := struct {
+		Properties map[string]struct {
+			Status *StatusConfig `json:"status"`
+		} `json:"properties"`
+	}{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return fmt.Errorf("decoding database %s: %w", id, err)
+	}
+
+	statuses := map[string]StatusConfig{}
+	for name, p := range raw.Properties {
+		if p.Status != nil {
+			statuses[name] = *p.Status
+		}
+	}
+
+	// copy the options so that the caller's slice is not written to
+	opts = append(append([]CompatibilityOption{}, opts...), DatabaseStatuses(statuses))
+
+	if err := Compatible(schema, db.Properties, opts...); err != nil {
+		err.ID = id
+		return err
+	}
+
+	return nil
+}
+
+// Compatible returns an *IncompatibleError if the properties of a database differ from the schema
+// in a way that code generated from the schema can't handle:
+// properties are missing or have another type, numbers have another format,
+// relations point to another database or selects have options the schema doesn't know.
+// With StatusOptions and DatabaseStatuses, the same goes for the options of statuses.
+//
+// Properties that are not part of the schema are ignored.
+func Compatible(schema, props notion.PropertyMetaMap, opts ...CompatibilityOption) *IncompatibleError {
+	o := &compatibilityOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	// the names of the properties, by ID
+	names := make(map[string]string, len(props))
+	for name, p := range props {
+		if p.Id != "" {
+			names[p.Id] = name
+		}
+	}
+
+	problems := []string{}
+
+	for name, want := range schema {
+		gotName := name
+		got, ok := props[name]
+
+		if renamed, found := names[want.Id]; want.Id != "" && found && renamed != name {
+			if !o.byID {
+				problems = append(problems, fmt.Sprintf("property %q was renamed to %q", name, renamed))
+				continue
+			}
+
+			gotName = renamed
+			got, ok = props[renamed], true
+		}
+
+		if !ok {
+			problems = append(problems, fmt.Sprintf("property %q is missing", name))
+			continue
+		}
+
+		problems = append(problems, incompatibilities(name, want, got)...)
+
+		if want.Type == PropertyTypeStatus && got.Type == PropertyTypeStatus {
+			problems = append(problems, unknownOptions(name,
+				statusOptions(o.statuses, name), statusOptions(o.databaseStatuses, gotName))...)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	sort.Strings(problems)
+
+	return &IncompatibleError{Problems: problems}
+}
+
+// incompatibilities returns how the property differs from the schema in an incompatible way.
+func incompatibilities(name string, want, got notion.PropertyMeta) []string {
+	if got.Type != want.Type {
+		return []string{fmt.Sprintf("property %q is %s instead of %s", name, got.Type, want.Type)}
+	}
+
+	problems := []string{}
+
+	switch want.Type {
+	case notion.PropertyTypeNumber:
+		if want.Number != nil && got.Number != nil && got.Number.Format != want.Number.Format {
+			problems = append(problems, fmt.Sprintf("number %q has format %s instead of %s",
+				name, got.Number.Format, want.Number.Format))
+		}
+	case notion.PropertyTypeRelation:
+		if want.Relation != nil && want.Relation.DatabaseId != "" && got.Relation != nil &&
+			NormalizeID(got.Relation.DatabaseId) != NormalizeID(want.Relation.DatabaseId) {
+			problems = append(problems, fmt.Sprintf("relation %q points to database %s instead of %s",
+				name, got.Relation.DatabaseId, want.Relation.DatabaseId))
+		}
+	case notion.PropertyTypeSelect:
+		problems = append(problems, unknownOptions(name, want.Select, got.Select)...)
+	case notion.PropertyTypeMultiSelect:
+		problems = append(problems, unknownOptions(name, want.MultiSelect, got.MultiSelect)...)
+	}
+
+	return problems
+}
+
+// statusOptions returns the options of the status with the given name, if there are any.
+func statusOptions(statuses map[string]StatusConfig, name string) *notion.PropertyOptionsWrapper {
+	c, ok := statuses[name]
+	if !ok {
+		return nil
+	}
+
+	return &notion.PropertyOptionsWrapper{Options: c.Options}
+}
+
+// unknownOptions returns the options the schema doesn't know, which generated code rejects.
+// If the schema has no options, all options are accepted.
+func unknownOptions(name string, want, got *notion.PropertyOptionsWrapper) []string {
+	if want == nil || len(want.Options) == 0 || got == nil {
+		return nil
+	}
+
+	known := make([]string, len(want.Options))
+	for i, opt := range want.Options {
+		known[i] = opt.Name
+	}
+
+	problems := []string{}
+
+	for _, opt := range got.Options {
+		if !contains(known, opt.Name) {
+			problems = append(problems, fmt.Sprintf("option %q of %q is unknown", opt.Name, name))
+		}
+	}
+
+	return problems
+}
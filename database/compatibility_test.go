@@ -0,0 +1,122 @@
+package database_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faetools/client"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompatible(t *testing.T) {
+	t.Parallel()
+
+	options := func(names ...string) *notion.PropertyOptionsWrapper {
+		w := &notion.PropertyOptionsWrapper{Options: []notion.PropertyOption{}}
+		for _, name := range names {
+			w.Options = append(w.Options, notion.PropertyOption{Name: name})
+		}
+
+		return w
+	}
+
+	schema := notion.PropertyMetaMap{
+		"Name":     {Id: "title", Type: notion.PropertyTypeTitle},
+		"Size":     {Type: notion.PropertyTypeSelect, Select: options("S", "M")},
+		"Tags":     {Type: notion.PropertyTypeMultiSelect, MultiSelect: options()},
+		"Projects": {Type: notion.PropertyTypeRelation, Relation: &notion.RelationConfiguration{DatabaseId: "a-b"}},
+	}
+
+	assert.Nil(t, database.Compatible(schema, schema))
+
+	props := notion.PropertyMetaMap{
+		"Title":    {Id: "title", Type: notion.PropertyTypeTitle},
+		"Size":     {Type: notion.PropertyTypeSelect, Select: options("M", "L")},
+		"Tags":     {Type: notion.PropertyTypeMultiSelect, MultiSelect: options("new")},
+		"Projects": {Type: notion.PropertyTypeRelation, Relation: &notion.RelationConfiguration{DatabaseId: "AB"}},
+		"Other":    {Type: notion.PropertyTypeCheckbox},
+	}
+
+	err := database.Compatible(schema, props)
+	require.NotNil(t, err)
+	assert.Equal(t, []string{
+		`option "L" of "Size" is unknown`,
+		`property "Name" was renamed to "Title"`,
+	}, err.Problems)
+
+	err = database.Compatible(schema, props, database.MatchByID())
+	require.NotNil(t, err)
+	assert.Equal(t, []string{`option "L" of "Size" is unknown`}, err.Problems)
+
+	props["Projects"] = notion.PropertyMeta{
+		Type: notion.PropertyTypeRelation, Relation: &notion.RelationConfiguration{DatabaseId: "c"},
+	}
+	delete(props, "Size")
+
+	err = database.Compatible(schema, props, database.MatchByID())
+	require.NotNil(t, err)
+	err.ID = "db"
+	assert.EqualError(t, err, `database db is incompatible: `+
+		`property "Size" is missing; relation "Projects" points to database c instead of a-b`)
+}
+
+func TestCompatible_Statuses(t *testing.T) {
+	t.Parallel()
+
+	schema := notion.PropertyMetaMap{"Stage": {Id: "s", Type: database.PropertyTypeStatus}}
+	props := notion.PropertyMetaMap{"Phase": {Id: "s", Type: database.PropertyTypeStatus}}
+
+	known := database.StatusOptions(map[string]database.StatusConfig{
+		"Stage": {Options: []notion.PropertyOption{{Name: "Todo"}, {Name: "Done"}}},
+	})
+	got := database.DatabaseStatuses(map[string]database.StatusConfig{
+		"Phase": {Options: []notion.PropertyOption{{Name: "Todo"}, {Name: "Blocked"}}},
+	})
+
+	// without options on both sides, there's nothing to compare
+	assert.Nil(t, database.Compatible(schema, props, database.MatchByID(), known))
+	assert.Nil(t, database.Compatible(schema, props, database.MatchByID(), got))
+
+	err := database.Compatible(schema, props, database.MatchByID(), known, got)
+	require.NotNil(t, err)
+	assert.Equal(t, []string{`option "Blocked" of "Stage" is unknown`}, err.Problems)
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/v1/databases/a%2Fb" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"database","id":"a/b","properties":{
+			"Stage":{"id":"s","type":"status","status":{"options":[{"name":"Todo"},{"name":"Blocked"}],"groups":[]}}
+		}}`))
+	}))
+	defer srv.Close()
+
+	cli, err := notion.NewDefaultClient("secret", client.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	schema := notion.PropertyMetaMap{"Stage": {Id: "s", Type: database.PropertyTypeStatus}}
+	ctx := context.Background()
+
+	require.NoError(t, database.CheckCompatibility(ctx, cli, "a/b", schema))
+
+	err = database.CheckCompatibility(ctx, cli, "a/b", schema, database.StatusOptions(map[string]database.StatusConfig{
+		"Stage": {Options: []notion.PropertyOption{{Name: "Todo"}, {Name: "Done"}}},
+	}))
+
+	incompatible := &database.IncompatibleError{}
+	require.ErrorAs(t, err, &incompatible)
+	assert.Equal(t, []string{`option "Blocked" of "Stage" is unknown`}, incompatible.Problems)
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// ConflictError is returned if a page was edited after it was loaded.
+type ConflictError struct {
+	// ID is the ID of the page.
+	ID notion.UUID
+	// Loaded is the time the page was last edited when it was loaded.
+	Loaded time.Time
+	// Current is the time the page was last edited now.
+	Current time.Time
+	// Properties are the properties that were changed by both, if changes were merged.
+	Properties []string
+}
+
+func (e *ConflictError) Error() string {
+	if len(e.Properties) > 0 {
+		return fmt.Sprintf("page %s was edited at %s after it was loaded, changing %s",
+			e.ID, e.Current.Format(time.RFC3339), strings.Join(e.Properties, ", "))
+	}
+
+	return fmt.Sprintf("page %s was edited at %s after it was loaded",
+		e.ID, e.Current.Format(time.RFC3339))
+}
+
+// MergeChanges makes updates succeed if the page was edited after it was loaded,
+// as long as other properties were changed than the ones being updated.
+func MergeChanges() UpdateOption {
+	return func(o *updateOptions) { o.merge = true }
+}
+
+// CheckConflict returns a *ConflictError if the page was edited after it was loaded.
+//
+// Notion only keeps the time of the last edit to the minute, so edits within the same minute can't be detected.
+// With MergeChanges, values are compared with their exact numbers and raw values, e.g. statuses.
+func CheckConflict(loaded, current Page, changes notion.PropertyValueMap, opts ...UpdateOption) error {
+	if current.LastEditedTime.Equal(loaded.LastEditedTime) {
+		return nil
+	}
+
+	o := newUpdateOptions(opts)
+
+	conflict := &ConflictError{
+		ID:      current.Id,
+		Loaded:  loaded.LastEditedTime,
+		Current: current.LastEditedTime,
+	}
+
+	if !o.merge {
+		return conflict
+	}
+
+	// the changes are keyed by the names the properties have in the schema
+	from, fromNums, fromRaw := ByID(o.ids, loaded.Properties, loaded.Numbers, loaded.Raw)
+	to, toNums, toRaw := ByID(o.ids, current.Properties, current.Numbers, current.Raw)
+
+	for key := range DiffRaw(from, to, fromNums, toNums, fromRaw, toRaw) {
+		if _, ok := changes[key]; ok {
+			conflict.Properties = append(conflict.Properties, key)
+		}
+	}
+
+	if len(conflict.Properties) == 0 {
+		return nil
+	}
+
+	sort.Strings(conflict.Properties)
+
+	return conflict
+}
+
+// UpdatePageIfUnchanged updates the given property values of the page,
+// unless it was edited after it was loaded, in which case a *ConflictError is returned.
+//
+// The page is requested again to compare the time it was last edited.
+// This narrows, but does not close, the window for conflicting edits.
+func UpdatePageIfUnchanged(ctx context.Context, cli *notion.Client, loaded Page,
+	props notion.PropertyValueMap, opts ...UpdateOption,
+) (*Page, error) {
+	current, err := GetPage(ctx, cli, notion.Id(loaded.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckConflict(loaded, *current, props, opts...); err != nil {
+		return nil, err
+	}
+
+	return UpdatePage(ctx, cli, notion.Id(loaded.Id), props, opts...)
+}
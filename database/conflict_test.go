@@ -0,0 +1,83 @@
+package database_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckConflict(t *testing.T) {
+	t.Parallel()
+
+	yes := true
+	title, other := notion.NewRichTexts("title"), notion.NewRichTexts("other")
+	loadedAt := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	loaded := database.Page{Page: notion.Page{
+		Id:             "page",
+		LastEditedTime: loadedAt,
+		Properties: notion.PropertyValueMap{
+			"Name": {Type: notion.PropertyTypeTitle, Title: &title},
+		},
+	}}
+
+	changes := notion.PropertyValueMap{"Done": {Type: notion.PropertyTypeCheckbox, Checkbox: &yes}}
+
+	assert.NoError(t, database.CheckConflict(loaded, loaded, changes))
+
+	current := loaded
+	current.LastEditedTime = loadedAt.Add(time.Minute)
+	current.Properties = notion.PropertyValueMap{
+		"Name": {Type: notion.PropertyTypeTitle, Title: &other},
+	}
+
+	err := database.CheckConflict(loaded, current, changes)
+
+	conflict := &database.ConflictError{}
+	if assert.True(t, errors.As(err, &conflict)) {
+		assert.Equal(t, loadedAt, conflict.Loaded)
+		assert.Equal(t, current.LastEditedTime, conflict.Current)
+		assert.Empty(t, conflict.Properties)
+	}
+
+	assert.NoError(t, database.CheckConflict(loaded, current, changes, database.MergeChanges()))
+
+	changes["Name"] = notion.PropertyValue{Type: notion.PropertyTypeTitle, Title: &title}
+
+	err = database.CheckConflict(loaded, current, changes, database.MergeChanges())
+	if assert.True(t, errors.As(err, &conflict)) {
+		assert.Equal(t, []string{"Name"}, conflict.Properties)
+	}
+
+	// numbers a float32 can't tell apart
+	views := float32(16777216)
+	loaded.Properties["Views"] = notion.PropertyValue{Type: notion.PropertyTypeNumber, Number: &views}
+	loaded.Numbers = database.Numbers{"Views": "16777216"}
+	current.Properties["Views"] = loaded.Properties["Views"]
+	current.Numbers = database.Numbers{"Views": "16777217"}
+	changes["Views"] = notion.PropertyValue{Type: notion.PropertyTypeNumber, Number: &views}
+
+	err = database.CheckConflict(loaded, current, changes, database.MergeChanges())
+	if assert.True(t, errors.As(err, &conflict)) {
+		assert.Equal(t, []string{"Name", "Views"}, conflict.Properties)
+	}
+
+	// statuses notion.PropertyValue can't hold
+	loaded.Properties["Stage"] = notion.PropertyValue{Type: database.PropertyTypeStatus}
+	loaded.Raw = database.RawValues{"Stage": database.Status("Todo")}
+	current.Properties["Stage"] = loaded.Properties["Stage"]
+	current.Raw = database.RawValues{"Stage": database.Status("Done")}
+	changes = notion.PropertyValueMap{"Stage": {Type: database.PropertyTypeStatus}}
+
+	err = database.CheckConflict(loaded, current, changes, database.MergeChanges())
+	if assert.True(t, errors.As(err, &conflict)) {
+		assert.Equal(t, []string{"Stage"}, conflict.Properties)
+	}
+
+	current.Raw = loaded.Raw
+	assert.NoError(t, database.CheckConflict(loaded, current, changes, database.MergeChanges()))
+}
@@ -0,0 +1,29 @@
+// Package database provides the helpers that the code generated by gen relies on.
+package database
+
+import (
+	"strings"
+
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// IDs returns the IDs of all references as IDs of the desired type.
+func IDs[T ~string](refs notion.References) []T {
+	if refs == nil {
+		return nil
+	}
+
+	ids := make([]T, len(refs))
+
+	for i, ref := range refs {
+		ids[i] = T(ref.Id)
+	}
+
+	return ids
+}
+
+// NormalizeID normalizes a notion ID, which can be given with or without dashes,
+// so that IDs can be compared.
+func NormalizeID(id notion.UUID) string {
+	return strings.ToLower(strings.ReplaceAll(string(id), "-", ""))
+}
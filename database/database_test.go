@@ -0,0 +1,26 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+)
+
+type myID notion.UUID
+
+func TestIDs(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, database.IDs[myID](nil))
+	assert.Equal(t, []myID{"a", "b"}, database.IDs[myID](notion.References{{Id: "a"}, {Id: "b"}}))
+}
+
+func TestNormalizeID(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "5f3a9c8e1d2b4c6a9e7f0a1b2c3d4e5f", database.NormalizeID("5F3A9C8E-1D2B-4C6A-9E7F-0A1B2C3D4E5F"))
+	assert.Equal(t, database.NormalizeID("5f3a9c8e1d2b4c6a9e7f0a1b2c3d4e5f"),
+		database.NormalizeID("5f3a9c8e-1d2b-4c6a-9e7f-0a1b2c3d4e5f"))
+}
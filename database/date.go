@@ -0,0 +1,280 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+const (
+	dateLayout      = "2006-01-02"
+	localTimeLayout = "2006-01-02T15:04:05.999999999"
+)
+
+// allDay is the location of the start and end of all-day dates in a notion.Date,
+// which can't tell them from times at midnight otherwise.
+var allDay = time.FixedZone("all-day", 0)
+
+// DateValue is the value of a date property: a date or a date range, each with or without time.
+type DateValue struct {
+	Start time.Time
+	// End is the end of a date range or zero if the date is no range.
+	End time.Time
+	// AllDay reports whether the date has no time. Start and End are at midnight UTC then.
+	AllDay bool
+	// TimeZone is the IANA time zone the date is shown in, e.g. Europe/Berlin.
+	// If it is empty, the date is shown with the offsets of Start and End.
+	TimeZone string
+}
+
+// NewDateValue returns the value of the date.
+func NewDateValue(d notion.Date) DateValue {
+	v := DateValue{Start: d.Start, AllDay: d.Start.Location() == allDay}
+
+	if d.End != nil {
+		v.End = *d.End
+	}
+
+	if d.TimeZone != nil {
+		v.TimeZone = *d.TimeZone
+	}
+
+	if v.AllDay {
+		v.Start, v.End = day(v.Start, time.UTC), day(v.End, time.UTC)
+	}
+
+	return v
+}
+
+// Date returns a pointer to the date as it is used in a property value or nil if it is not set.
+func (v DateValue) Date() *notion.Date {
+	if v.Start.IsZero() {
+		return nil
+	}
+
+	d := &notion.Date{Start: v.Start}
+
+	if !v.End.IsZero() {
+		end := v.End
+		d.End = &end
+	}
+
+	if v.TimeZone != "" {
+		tz := v.TimeZone
+		d.TimeZone = &tz
+	}
+
+	if v.AllDay {
+		d.Start = day(d.Start, allDay)
+
+		if d.End != nil {
+			end := day(*d.End, allDay)
+			d.End = &end
+		}
+	}
+
+	return d
+}
+
+// IsZero reports whether the date is not set.
+func (v DateValue) IsZero() bool { return v.Start.IsZero() }
+
+// IsRange reports whether the date is a date range.
+func (v DateValue) IsRange() bool { return !v.End.IsZero() }
+
+// IsAllDay reports whether the date has no time.
+func (v DateValue) IsAllDay() bool { return v.AllDay }
+
+// Duration returns how long the date lasts.
+// All-day dates last until the end of their last day, other dates without end don't last.
+func (v DateValue) Duration() time.Duration {
+	end := v.End
+	if end.IsZero() {
+		end = v.Start
+	}
+
+	if v.AllDay {
+		return end.AddDate(0, 0, 1).Sub(v.Start)
+	}
+
+	return end.Sub(v.Start)
+}
+
+// Location returns the location of the time zone of the date.
+// If the date has no time zone or it is unknown, the location of its start is returned.
+func (v DateValue) Location() *time.Location {
+	if v.TimeZone != "" {
+		if loc, err := time.LoadLocation(v.TimeZone); err == nil {
+			return loc
+		}
+	}
+
+	return v.Start.Location()
+}
+
+// In returns the date with its times in the given location.
+// All-day dates have no times and are returned as they are.
+func (v DateValue) In(loc *time.Location) DateValue {
+	if v.AllDay {
+		return v
+	}
+
+	v.Start = v.Start.In(loc)
+
+	if !v.End.IsZero() {
+		v.End = v.End.In(loc)
+	}
+
+	return v
+}
+
+// Contains reports whether the time lies within the date.
+// For all-day dates, the day of the time in the time zone of the date is used.
+func (v DateValue) Contains(t time.Time) bool {
+	if v.IsZero() {
+		return false
+	}
+
+	if v.AllDay {
+		if v.TimeZone != "" {
+			t = t.In(v.Location())
+		}
+
+		t = day(t, time.UTC)
+	}
+
+	end := v.Start.Add(v.Duration())
+
+	if v.AllDay {
+		return !t.Before(v.Start) && t.Before(end)
+	}
+
+	return !t.Before(v.Start) && !t.After(end)
+}
+
+func (v DateValue) String() string {
+	if v.IsZero() {
+		return ""
+	}
+
+	s := v.format(v.Start)
+
+	if v.IsRange() {
+		s += " → " + v.format(v.End)
+	}
+
+	if v.TimeZone != "" {
+		s += " (" + v.TimeZone + ")"
+	}
+
+	return s
+}
+
+func (v DateValue) format(t time.Time) string {
+	switch {
+	case v.AllDay:
+		return t.Format(dateLayout)
+	case v.TimeZone != "":
+		return t.In(v.Location()).Format(localTimeLayout)
+	default:
+		return t.Format(time.RFC3339Nano)
+	}
+}
+
+// day returns the midnight of the day of the time in the given location.
+func day(t time.Time, loc *time.Location) time.Time {
+	if t.IsZero() {
+		return t
+	}
+
+	y, m, d := t.Date()
+
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// rawDate is a date as Notion sends it.
+type rawDate struct {
+	Start    string  `json:"start"`
+	End      *string `json:"end"`
+	TimeZone *string `json:"time_zone"`
+}
+
+// decode returns the date, which may be all-day or given in its time zone without offset.
+func (d rawDate) decode() (notion.Date, error) {
+	loc := time.UTC
+
+	if d.TimeZone != nil {
+		var err error
+		if loc, err = time.LoadLocation(*d.TimeZone); err != nil {
+			return notion.Date{}, err
+		}
+	}
+
+	start, err := parseDate(d.Start, loc)
+	if err != nil {
+		return notion.Date{}, err
+	}
+
+	date := notion.Date{Start: start, TimeZone: d.TimeZone}
+
+	if d.End != nil {
+		end, err := parseDate(*d.End, loc)
+		if err != nil {
+			return notion.Date{}, err
+		}
+
+		date.End = &end
+	}
+
+	return date, nil
+}
+
+// parseDate parses the date, which is all-day if it has no time
+// or in the given location if it has no offset.
+func parseDate(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation(dateLayout, s, allDay); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation(localTimeLayout, s, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
+	}
+
+	return t, nil
+}
+
+// encodeDate returns the date as Notion expects it:
+// all-day dates without time and dates with a time zone without offset.
+func encodeDate(d notion.Date) (json.RawMessage, error) {
+	format := func(t time.Time) string { return t.Format(time.RFC3339Nano) }
+	tz := d.TimeZone
+
+	switch {
+	case d.Start.Location() == allDay:
+		// all-day dates can't have a time zone
+		format, tz = func(t time.Time) string { return t.Format(dateLayout) }, nil
+	case tz != nil:
+		loc, err := time.LoadLocation(*d.TimeZone)
+		if err != nil {
+			return nil, err
+		}
+
+		format = func(t time.Time) string { return t.In(loc).Format(localTimeLayout) }
+	}
+
+	raw := rawDate{Start: format(d.Start), TimeZone: tz}
+
+	if d.End != nil {
+		end := format(*d.End)
+		raw.End = &end
+	}
+
+	return json.Marshal(raw)
+}
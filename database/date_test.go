@@ -0,0 +1,88 @@
+package database_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateValue(t *testing.T) {
+	t.Parallel()
+
+	day := func(d int) time.Time { return time.Date(2022, 5, d, 0, 0, 0, 0, time.UTC) }
+
+	holiday := database.DateValue{Start: day(1), End: day(3), AllDay: true}
+	assert.True(t, holiday.IsRange())
+	assert.True(t, holiday.IsAllDay())
+	assert.Equal(t, 72*time.Hour, holiday.Duration())
+	assert.True(t, holiday.Contains(day(3).Add(23*time.Hour)))
+	assert.False(t, holiday.Contains(day(4)))
+	assert.Equal(t, "2022-05-01 → 2022-05-03", holiday.String())
+	assert.Equal(t, holiday, database.NewDateValue(*holiday.Date()))
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	meeting := database.DateValue{
+		Start:    time.Date(2022, 5, 1, 10, 0, 0, 0, berlin),
+		End:      time.Date(2022, 5, 1, 11, 30, 0, 0, berlin),
+		TimeZone: "Europe/Berlin",
+	}
+	assert.False(t, meeting.IsAllDay())
+	assert.Equal(t, 90*time.Minute, meeting.Duration())
+	assert.Equal(t, berlin, meeting.Location())
+	assert.True(t, meeting.Contains(time.Date(2022, 5, 1, 8, 0, 0, 0, time.UTC)))
+	assert.False(t, meeting.Contains(time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "2022-05-01T08:00:00Z", meeting.In(time.UTC).Start.Format(time.RFC3339))
+	assert.Equal(t, "2022-05-01T10:00:00 → 2022-05-01T11:30:00 (Europe/Berlin)", meeting.String())
+
+	assert.Equal(t, holiday, holiday.In(berlin))
+	assert.Zero(t, database.DateValue{}.Duration())
+	assert.False(t, database.DateValue{}.Contains(day(1)))
+	assert.Nil(t, database.DateValue{}.Date())
+}
+
+func TestPage_Dates(t *testing.T) {
+	t.Parallel()
+
+	const raw = `{"object":"page","id":"page","properties":{
+		"Holiday":{"type":"date","date":{"start":"2022-05-01","end":"2022-05-03"}},
+		"Meeting":{"type":"date","date":{"start":"2022-05-01T10:00:00.000","time_zone":"Europe/Berlin"}},
+		"Due":{"type":"date","date":{"start":"2022-05-01T10:00:00.000+02:00"}}
+	}}`
+
+	p := database.Page{}
+	require.NoError(t, json.Unmarshal([]byte(raw), &p))
+
+	holiday := database.NewDateValue(p.Properties["Holiday"].GetDate())
+	assert.True(t, holiday.IsAllDay())
+	assert.Equal(t, time.Date(2022, 5, 3, 0, 0, 0, 0, time.UTC), holiday.End)
+
+	meeting := database.NewDateValue(p.Properties["Meeting"].GetDate())
+	assert.False(t, meeting.IsAllDay())
+	assert.Equal(t, "2022-05-01T08:00:00Z", meeting.Start.UTC().Format(time.RFC3339))
+
+	due := database.NewDateValue(p.Properties["Due"].GetDate())
+	assert.Equal(t, "2022-05-01T08:00:00Z", due.Start.UTC().Format(time.RFC3339))
+
+	b, err := json.Marshal(p)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(b), `"date":{"start":"2022-05-01","end":"2022-05-03","time_zone":null}`)
+	assert.Contains(t, string(b), `"date":{"start":"2022-05-01T10:00:00","end":null,"time_zone":"Europe/Berlin"}`)
+	assert.Contains(t, string(b), `"date":{"start":"2022-05-01T10:00:00+02:00","end":null,"time_zone":null}`)
+
+	got := database.Page{}
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Empty(t, database.Diff(p.Properties, got.Properties))
+
+	// all-day dates differ from times at midnight
+	v := p.Properties["Holiday"]
+	v.Date = database.DateValue{Start: holiday.Start, End: holiday.End}.Date()
+	assert.Len(t, database.Diff(p.Properties, notion.PropertyValueMap{"Holiday": v}), 1)
+}
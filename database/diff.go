@@ -0,0 +1,136 @@
+package database
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// Diff returns the property values of to that differ from those of from.
+//
+// Values are compared by what is sent to Notion, so a value that is nil equals one that is empty.
+func Diff(from, to notion.PropertyValueMap) notion.PropertyValueMap {
+	changes := notion.PropertyValueMap{}
+
+	for key, v := range to {
+		if old, ok := from[key]; !ok || !equal(old, v) {
+			changes[key] = v
+		}
+	}
+
+	return changes
+}
+
+// DiffExact is like Diff, but also compares the exact numbers of the property values.
+func DiffExact(from, to notion.PropertyValueMap, fromNums, toNums Numbers) notion.PropertyValueMap {
+	changes := Diff(from, to)
+
+	for key, n := range toNums {
+		if _, ok := changes[key]; ok {
+			continue
+		}
+
+		if v, ok := to[key]; ok && !sameNumber(fromNums[key], n) {
+			changes[key] = v
+		}
+	}
+
+	return changes
+}
+
+// DiffRaw is like DiffExact, but also compares the raw values of the property values.
+func DiffRaw(from, to notion.PropertyValueMap, fromNums, toNums Numbers, fromRaw, toRaw RawValues) notion.PropertyValueMap {
+	changes := DiffExact(from, to, fromNums, toNums)
+
+	for key, raw := range toRaw {
+		if _, ok := changes[key]; ok {
+			continue
+		}
+
+		if v, ok := to[key]; ok && !sameRaw(fromRaw[key], raw) {
+			changes[key] = v
+		}
+	}
+
+	return changes
+}
+
+// sameRaw reports whether both raw values are the same, apart from empty values.
+func sameRaw(a, b json.RawMessage) bool {
+	var decodedA, decodedB interface{}
+
+	if err := json.Unmarshal(a, &decodedA); err != nil && len(a) > 0 {
+		return false
+	}
+
+	if err := json.Unmarshal(b, &decodedB); err != nil && len(b) > 0 {
+		return false
+	}
+
+	return reflect.DeepEqual(withoutEmpty(decodedA), withoutEmpty(decodedB))
+}
+
+// sameNumber reports whether both JSON numbers have the same value.
+func sameNumber(a, b json.Number) bool {
+	ra, okA := Decimal(a).Rat()
+	rb, okB := Decimal(b).Rat()
+
+	if !okA || !okB {
+		return a == b
+	}
+
+	return ra.Cmp(rb) == 0
+}
+
+// equal reports whether both values are encoded the same way, apart from empty values.
+func equal(a, b notion.PropertyValue) bool {
+	return reflect.DeepEqual(encode(a), encode(b))
+}
+
+// encode returns the value as it would be sent to Notion, without any empty values.
+func encode(v notion.PropertyValue) interface{} {
+	b, err := encodeValue(v, "", nil)
+	if err != nil {
+		return v
+	}
+
+	var res interface{}
+	if err := json.Unmarshal(b, &res); err != nil {
+		return v
+	}
+
+	return withoutEmpty(res)
+}
+
+// withoutEmpty returns the decoded JSON without nulls, empty arrays and empty objects.
+func withoutEmpty(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, sub := range v {
+			if sub = withoutEmpty(sub); sub == nil {
+				delete(v, k)
+			} else {
+				v[k] = sub
+			}
+		}
+
+		if len(v) == 0 {
+			return nil
+		}
+
+		return v
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+
+		for i, sub := range v {
+			v[i] = withoutEmpty(sub)
+		}
+
+		return v
+	default:
+		return v
+	}
+}
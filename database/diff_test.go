@@ -0,0 +1,36 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	yes, no := true, false
+	title, other := notion.NewRichTexts("title"), notion.NewRichTexts("other")
+	empty, none := notion.RichTexts{}, notion.RichTexts(nil)
+
+	from := notion.PropertyValueMap{
+		"Name":  {Type: notion.PropertyTypeTitle, Title: &title},
+		"Done":  {Type: notion.PropertyTypeCheckbox, Checkbox: &no},
+		"Notes": {Type: notion.PropertyTypeRichText, RichText: &empty},
+	}
+
+	assert.Empty(t, database.Diff(from, from))
+
+	assert.Equal(t, notion.PropertyValueMap{
+		"Name": {Type: notion.PropertyTypeTitle, Title: &other},
+		"Done": {Type: notion.PropertyTypeCheckbox, Checkbox: &yes},
+		"New":  {Type: notion.PropertyTypeCheckbox, Checkbox: &yes},
+	}, database.Diff(from, notion.PropertyValueMap{
+		"Name":  {Type: notion.PropertyTypeTitle, Title: &other},
+		"Done":  {Type: notion.PropertyTypeCheckbox, Checkbox: &yes},
+		"Notes": {Type: notion.PropertyTypeRichText, RichText: &none},
+		"New":   {Type: notion.PropertyTypeCheckbox, Checkbox: &yes},
+	}))
+}
@@ -0,0 +1,242 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+const (
+	defaultConcurrency = 3
+	defaultMaxRetries  = 5
+	defaultBackoff     = time.Second
+)
+
+// Graph holds pages and the pages they relate to, with their exact numbers and raw values.
+type Graph struct {
+	mu    sync.Mutex
+	pages map[string]Page
+}
+
+func newGraph() *Graph { return &Graph{pages: map[string]Page{}} }
+
+// Page returns the page with the given ID, if it is part of the graph.
+func (g *Graph) Page(id notion.UUID) (Page, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	p, ok := g.pages[NormalizeID(id)]
+
+	return p, ok
+}
+
+// Len returns the number of pages in the graph.
+func (g *Graph) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return len(g.pages)
+}
+
+// add adds the page and reports whether it was new to the graph.
+func (g *Graph) add(p Page) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	id := NormalizeID(p.Id)
+	if _, ok := g.pages[id]; ok {
+		return false
+	}
+
+	g.pages[id] = p
+
+	return true
+}
+
+func (g *Graph) has(id notion.UUID) bool {
+	_, ok := g.Page(id)
+	return ok
+}
+
+// Related returns the entries with the given IDs that are part of the graph.
+func Related[E any, ID ~string](g *Graph, ids []ID, newEntry func(Page) E) []E {
+	entries := make([]E, 0, len(ids))
+
+	for _, id := range ids {
+		if p, ok := g.Page(notion.UUID(id)); ok {
+			entries = append(entries, newEntry(p))
+		}
+	}
+
+	return entries
+}
+
+type expandOptions struct {
+	concurrency int
+	maxRetries  int
+	backoff     time.Duration
+}
+
+// ExpandOption sets an option for expanding relations.
+type ExpandOption func(*expandOptions)
+
+// Concurrency sets how many pages are requested at the same time.
+func Concurrency(n int) ExpandOption {
+	return func(o *expandOptions) { o.concurrency = n }
+}
+
+// MaxRetries sets how often a request is retried after Notion responded with 429 Too Many Requests.
+func MaxRetries(n int) ExpandOption {
+	return func(o *expandOptions) { o.maxRetries = n }
+}
+
+// Backoff sets how long to wait before retrying the first time.
+// The time doubles with each retry.
+func Backoff(d time.Duration) ExpandOption {
+	return func(o *expandOptions) { o.backoff = d }
+}
+
+// Expand returns a graph of the pages and all pages they relate to, up to the given depth.
+//
+// Every page is requested only once, even if several pages relate to it.
+func Expand(ctx context.Context, cli *notion.Client, pages []Page, depth int, opts ...ExpandOption) (*Graph, error) {
+	o := &expandOptions{
+		concurrency: defaultConcurrency,
+		maxRetries:  defaultMaxRetries,
+		backoff:     defaultBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.concurrency < 1 {
+		o.concurrency = 1
+	}
+
+	g := newGraph()
+
+	level := []Page{}
+
+	for _, p := range pages {
+		if g.add(p) {
+			level = append(level, p)
+		}
+	}
+
+	for ; depth > 0 && len(level) > 0; depth-- {
+		var err error
+
+		level, err = expandLevel(ctx, cli, g, relatedIDs(g, level), o)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+// relatedIDs returns the IDs of all pages the pages relate to and that are not yet part of the graph.
+func relatedIDs(g *Graph, pages []Page) []notion.UUID {
+	seen := map[string]bool{}
+	ids := []notion.UUID{}
+
+	for _, p := range pages {
+		for _, v := range p.Properties {
+			for _, ref := range v.GetRelation() {
+				id := NormalizeID(ref.Id)
+				if seen[id] || g.has(ref.Id) {
+					continue
+				}
+
+				seen[id] = true
+				ids = append(ids, ref.Id)
+			}
+		}
+	}
+
+	return ids
+}
+
+// expandLevel gets all pages with the given IDs and adds them to the graph.
+func expandLevel(ctx context.Context, cli *notion.Client, g *Graph, ids []notion.UUID, o *expandOptions,
+) ([]Page, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan notion.UUID)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		level    = make([]Page, 0, len(ids))
+	)
+
+	for i := 0; i < o.concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for id := range jobs {
+				p, err := getPage(ctx, cli, id, o)
+
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+
+				if err == nil && g.add(*p) {
+					level = append(level, *p)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, id := range ids {
+		select {
+		case jobs <- id:
+		case <-ctx.Done():
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return level, ctx.Err()
+}
+
+// getPage gets the page like GetPage, retrying with an exponential backoff if there were too many requests.
+func getPage(ctx context.Context, cli *notion.Client, id notion.UUID, o *expandOptions) (*Page, error) {
+	wait := o.backoff
+
+	for retry := 0; ; retry++ {
+		p, err := GetPage(ctx, cli, notion.Id(id))
+		if err == nil || retry == o.maxRetries || !isTooManyRequests(err) {
+			return p, err
+		}
+
+		select {
+		case <-time.After(wait):
+			wait *= 2
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func isTooManyRequests(err error) bool {
+	var notionErr *notion.Error
+	return errors.As(err, &notionErr) && notionErr.Status == http.StatusTooManyRequests
+}
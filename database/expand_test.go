@@ -0,0 +1,107 @@
+package database_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/faetools/client"
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func relatedPage(id notion.UUID, related ...notion.UUID) notion.Page {
+	refs := make(notion.References, len(related))
+	for i, r := range related {
+		refs[i] = notion.Reference{Id: r}
+	}
+
+	return notion.Page{
+		Id:         id,
+		Properties: notion.PropertyValueMap{"Related": {Relation: &refs}},
+	}
+}
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]notion.Page{
+		"b": relatedPage("b", "c", "d"),
+		"c": relatedPage("c", "a"),
+		"d": relatedPage("d", "e"),
+		"e": relatedPage("e"),
+	}
+
+	var (
+		mu       sync.Mutex
+		requests = map[string]int{}
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := path.Base(r.URL.Path)
+
+		mu.Lock()
+		requests[id]++
+		n := requests[id]
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if id == "c" && n == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_ = json.NewEncoder(w).Encode(notion.Error{Status: http.StatusTooManyRequests, Code: "rate_limited"})
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(pages[id])
+	}))
+	defer srv.Close()
+
+	cli, err := notion.NewDefaultClient("secret", client.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	g, err := database.Expand(context.Background(), cli,
+		[]database.Page{{Page: relatedPage("a", "b", "c")}}, 2,
+		database.Concurrency(2), database.Backoff(time.Millisecond))
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, g.Len())
+	assert.Equal(t, map[string]int{"b": 1, "c": 2, "d": 1}, requests)
+
+	_, ok := g.Page("d")
+	assert.True(t, ok)
+
+	_, ok = g.Page("e")
+	assert.False(t, ok)
+
+	related := database.Related(g, []string{"c", "d", "e"}, func(p database.Page) notion.UUID { return p.Id })
+	assert.Equal(t, []notion.UUID{"c", "d"}, related)
+}
+
+func TestExpand_Error(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_ = json.NewEncoder(w).Encode(notion.Error{Status: http.StatusTooManyRequests, Code: "rate_limited"})
+	}))
+	defer srv.Close()
+
+	cli, err := notion.NewDefaultClient("secret", client.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	_, err = database.Expand(context.Background(), cli, []database.Page{{Page: relatedPage("a", "b")}}, 1,
+		database.MaxRetries(1), database.Backoff(time.Millisecond))
+
+	var notionErr *notion.Error
+	assert.ErrorAs(t, err, &notionErr)
+}
@@ -0,0 +1,255 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/spf13/afero"
+)
+
+// ManifestFile is the name of the manifest DownloadFiles writes next to the files.
+// It holds a Manifest for each page and property whose files were downloaded into the directory.
+const ManifestFile = "manifest.json"
+
+// expiryMargin is how long before they expire URLs of files hosted by Notion are refreshed.
+const expiryMargin = time.Minute
+
+// Manifest lists the files of a property that were downloaded.
+type Manifest struct {
+	Page        notion.Id    `json:"page"`
+	Property    string       `json:"property"`
+	Attachments []Attachment `json:"attachments"`
+}
+
+// Attachment is a file that was downloaded.
+type Attachment struct {
+	// Path is the path of the file in the file system.
+	Path string `json:"path"`
+	// URL is the URL the file was downloaded from.
+	// For files hosted by Notion, it has no query, which only holds temporary credentials.
+	URL string `json:"url"`
+	// External reports whether the file is not hosted by Notion.
+	External bool   `json:"external"`
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256"`
+}
+
+type downloadOptions struct {
+	client *http.Client
+}
+
+// DownloadOption sets an option for downloading files.
+type DownloadOption func(*downloadOptions)
+
+// DownloadClient makes downloads use the given client instead of http.DefaultClient.
+func DownloadClient(c *http.Client) DownloadOption {
+	return func(o *downloadOptions) { o.client = c }
+}
+
+// DownloadFiles downloads the files of the property of the page into the directory
+// and adds a manifest of them to the manifests in the directory, replacing any earlier one
+// of the same page and property. It returns the manifest.
+//
+// The URLs of files hosted by Notion expire after an hour.
+// If one of them has expired or is rejected, the files are taken from the page, loaded anew.
+func DownloadFiles(ctx context.Context, cli *notion.Client, fs afero.Fs, dir string,
+	pageID notion.Id, prop string, files notion.Files, opts ...DownloadOption,
+) (Manifest, error) {
+	o := &downloadOptions{client: http.DefaultClient}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	refresh := func() error {
+		p, err := GetPage(ctx, cli, pageID)
+		if err != nil {
+			return fmt.Errorf("refreshing files: %w", err)
+		}
+
+		files = p.Properties[prop].GetFiles()
+
+		return nil
+	}
+
+	if expired(files) {
+		if err := refresh(); err != nil {
+			return Manifest{}, err
+		}
+	}
+
+	if err := fs.MkdirAll(dir, 0o755); err != nil {
+		return Manifest{}, err
+	}
+
+	m := Manifest{Page: pageID, Property: prop, Attachments: []Attachment{}}
+	refreshed := false
+
+	for i := 0; i < len(files); i++ {
+		a, err := download(ctx, o.client, fs, dir, i, files[i])
+		if errors.Is(err, errRejected) && !refreshed {
+			// the URL may have expired in the meantime
+			if err := refresh(); err != nil {
+				return Manifest{}, err
+			}
+
+			refreshed = true
+			i--
+
+			continue
+		}
+
+		if err != nil {
+			return Manifest{}, fmt.Errorf("downloading file %d of %q: %w", i+1, prop, err)
+		}
+
+		m.Attachments = append(m.Attachments, a)
+	}
+
+	if err := writeManifest(fs, filepath.Join(dir, ManifestFile), m); err != nil {
+		return Manifest{}, fmt.Errorf("writing manifest: %w", err)
+	}
+
+	return m, nil
+}
+
+// writeManifest adds the manifest to the manifests in the file,
+// replacing the one of the same page and property.
+func writeManifest(fs afero.Fs, name string, m Manifest) error {
+	ms := []Manifest{}
+
+	b, err := afero.ReadFile(fs, name)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(b, &ms); err != nil {
+			return err
+		}
+	}
+
+	found := false
+
+	for i := range ms {
+		if ms[i].Page == m.Page && ms[i].Property == m.Property {
+			ms[i], found = m, true
+		}
+	}
+
+	if !found {
+		ms = append(ms, m)
+	}
+
+	b, err = json.MarshalIndent(ms, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(fs, name, b, 0o644)
+}
+
+// errRejected is returned if the URL of a file hosted by Notion is rejected.
+var errRejected = errors.New("url rejected")
+
+// expired reports whether the URL of any file hosted by Notion has expired or is about to.
+func expired(files notion.Files) bool {
+	deadline := time.Now().Add(expiryMargin)
+
+	for _, f := range files {
+		if f.File != nil && !f.File.ExpiryTime.IsZero() && f.File.ExpiryTime.Before(deadline) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// download downloads the file into the directory.
+func download(ctx context.Context, c *http.Client, fs afero.Fs, dir string, i int, f notion.File) (Attachment, error) {
+	a := Attachment{}
+
+	switch {
+	case f.File != nil:
+		a.URL = f.File.Url
+	case f.External != nil:
+		a.URL, a.External = f.External.Url, true
+	default:
+		return Attachment{}, fmt.Errorf("file has no URL")
+	}
+
+	u, err := url.Parse(a.URL)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.URL, http.NoBody)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return Attachment{}, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case !a.External && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusBadRequest):
+		return Attachment{}, errRejected
+	case resp.StatusCode != http.StatusOK:
+		return Attachment{}, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	// files of different URLs may have the same name
+	a.Path = filepath.Join(dir, strconv.Itoa(i+1)+"-"+fileName(u))
+
+	out, err := fs.Create(a.Path)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	h := sha256.New()
+
+	a.Size, err = io.Copy(io.MultiWriter(out, h), resp.Body)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		// don't leave a partial file behind
+		_ = fs.Remove(a.Path)
+		return Attachment{}, err
+	}
+
+	a.SHA256 = hex.EncodeToString(h.Sum(nil))
+
+	if !a.External {
+		u.RawQuery = ""
+		a.URL = u.String()
+	}
+
+	return a, nil
+}
+
+// fileName returns the name of the file at the URL.
+func fileName(u *url.URL) string {
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		return "file"
+	}
+
+	return name
+}
@@ -0,0 +1,158 @@
+package database_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/faetools/client"
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func TestDownloadFiles(t *testing.T) {
+	t.Parallel()
+
+	var refreshes int32
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	hosted := func(sig string, expiry time.Time) notion.File {
+		return notion.File{Type: notion.FileTypeFile, File: &notion.NotionFile{
+			Url:        srv.URL + "/s3/report.pdf?sig=" + sig,
+			ExpiryTime: expiry,
+		}}
+	}
+
+	external := notion.File{Type: notion.FileTypeExternal, External: &notion.ExternalFile{Url: srv.URL + "/logo.png"}}
+
+	mux.HandleFunc("/v1/pages/page", func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&refreshes, 1)
+
+		files := notion.Files{hosted("fresh", time.Now().Add(time.Hour)), external}
+		assert.NoError(t, json.NewEncoder(w).Encode(notion.Page{
+			Object:     "page",
+			Id:         "page",
+			Properties: notion.PropertyValueMap{"Files": {Type: notion.PropertyTypeFiles, Files: &files}},
+		}))
+	})
+
+	mux.HandleFunc("/s3/report.pdf", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sig") != "fresh" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		fmt.Fprint(w, "report")
+	})
+
+	mux.HandleFunc("/logo.png", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "logo")
+	})
+
+	cli, err := notion.NewDefaultClient("secret", client.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	for name, files := range map[string]notion.Files{
+		"expired":  {hosted("old", time.Now().Add(-time.Minute)), external},
+		"rejected": {hosted("revoked", time.Now().Add(time.Hour)), external},
+	} {
+		atomic.StoreInt32(&refreshes, 0)
+		fs := afero.NewMemMapFs()
+
+		m, err := database.DownloadFiles(ctx, cli, fs, "out", "page", "Files", files)
+		require.NoError(t, err, name)
+		assert.EqualValues(t, 1, atomic.LoadInt32(&refreshes), name)
+
+		assert.Equal(t, []database.Attachment{
+			{
+				Path:   "out/1-report.pdf",
+				URL:    srv.URL + "/s3/report.pdf",
+				Size:   6,
+				SHA256: sha("report"),
+			},
+			{
+				Path:     "out/2-logo.png",
+				URL:      srv.URL + "/logo.png",
+				External: true,
+				Size:     4,
+				SHA256:   sha("logo"),
+			},
+		}, m.Attachments, name)
+
+		b, err := afero.ReadFile(fs, "out/1-report.pdf")
+		require.NoError(t, err)
+		assert.Equal(t, "report", string(b))
+
+		b, err = afero.ReadFile(fs, "out/"+database.ManifestFile)
+		require.NoError(t, err)
+
+		stored := []database.Manifest{}
+		require.NoError(t, json.Unmarshal(b, &stored))
+		assert.Equal(t, []database.Manifest{m}, stored)
+
+		// downloading again replaces the manifest
+		m, err = database.DownloadFiles(ctx, cli, fs, "out", "page", "Files", notion.Files{external})
+		require.NoError(t, err, name)
+
+		// the manifest of another property is kept next to it
+		other, err := database.DownloadFiles(ctx, cli, fs, "out", "page", "Logo", notion.Files{external})
+		require.NoError(t, err, name)
+
+		b, err = afero.ReadFile(fs, "out/"+database.ManifestFile)
+		require.NoError(t, err)
+
+		stored = []database.Manifest{}
+		require.NoError(t, json.Unmarshal(b, &stored))
+		assert.Equal(t, []database.Manifest{m, other}, stored, name)
+	}
+
+	// only the URLs of files hosted by Notion are refreshed
+	mux.HandleFunc("/gone.txt", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err = database.DownloadFiles(ctx, cli, afero.NewMemMapFs(), "out", "page", "Files", notion.Files{{
+		Type:     notion.FileTypeExternal,
+		External: &notion.ExternalFile{Url: srv.URL + "/gone.txt"},
+	}})
+	assert.EqualError(t, err, `downloading file 1 of "Files": unexpected status 404 Not Found`)
+
+	// files that were cut off are not left behind
+	mux.HandleFunc("/cut.txt", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		fmt.Fprint(w, "partial")
+	})
+
+	fs := afero.NewMemMapFs()
+
+	_, err = database.DownloadFiles(ctx, cli, fs, "out", "page", "Files", notion.Files{{
+		Type:     notion.FileTypeExternal,
+		External: &notion.ExternalFile{Url: srv.URL + "/cut.txt"},
+	}})
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	exists, err := afero.Exists(fs, "out/1-cut.txt")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,108 @@
+package database
+
+import "encoding/json"
+
+// FormulaType is the type of the result of a formula.
+type FormulaType string
+
+// The types of the results of formulas.
+const (
+	FormulaString  FormulaType = "string"
+	FormulaNumber  FormulaType = "number"
+	FormulaBoolean FormulaType = "boolean"
+	FormulaDate    FormulaType = "date"
+)
+
+// Formula is the result of a formula, which holds a value of its type.
+type Formula struct {
+	Type    FormulaType
+	String  string
+	Number  float64
+	Boolean bool
+	Date    DateValue
+}
+
+// rawFormula is a formula as Notion sends it.
+type rawFormula struct {
+	Type    FormulaType  `json:"type"`
+	String  *string      `json:"string,omitempty"`
+	Number  *json.Number `json:"number,omitempty"`
+	Boolean *bool        `json:"boolean,omitempty"`
+	Date    *rawDate     `json:"date,omitempty"`
+}
+
+// NewFormula decodes the result of a formula.
+// The result is empty if the formula can't be decoded.
+func NewFormula(raw json.RawMessage) Formula {
+	r := rawFormula{}
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return Formula{}
+	}
+
+	f := Formula{Type: r.Type}
+
+	if r.String != nil {
+		f.String = *r.String
+	}
+
+	if r.Number != nil {
+		f.Number, _ = r.Number.Float64()
+	}
+
+	if r.Boolean != nil {
+		f.Boolean = *r.Boolean
+	}
+
+	if r.Date != nil {
+		if d, err := r.Date.decode(); err == nil {
+			f.Date = NewDateValue(d)
+		}
+	}
+
+	return f
+}
+
+// MarshalJSON encodes the formula as Notion sends it.
+func (f Formula) MarshalJSON() ([]byte, error) {
+	r := rawFormula{Type: f.Type}
+
+	switch f.Type {
+	case FormulaString:
+		r.String = &f.String
+	case FormulaNumber:
+		n := Float64Number(f.Number)
+		r.Number = &n
+	case FormulaBoolean:
+		r.Boolean = &f.Boolean
+	case FormulaDate:
+		if d := f.Date.Date(); d != nil {
+			b, err := encodeDate(*d)
+			if err != nil {
+				return nil, err
+			}
+
+			r.Date = &rawDate{}
+			if err := json.Unmarshal(b, r.Date); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return json.Marshal(r)
+}
+
+// Value returns the value of the formula.
+func (f Formula) Value() interface{} {
+	switch f.Type {
+	case FormulaString:
+		return f.String
+	case FormulaNumber:
+		return f.Number
+	case FormulaBoolean:
+		return f.Boolean
+	case FormulaDate:
+		return f.Date
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,56 @@
+package database_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormula(t *testing.T) {
+	t.Parallel()
+
+	const raw = `{"object":"page","id":"page","properties":{
+		"Name":{"id":"title","type":"title","title":[]},
+		"Total":{"id":"a","type":"formula","formula":{"type":"number","number":16777217}},
+		"Label":{"id":"b","type":"formula","formula":{"type":"string","string":"big"}},
+		"Late":{"id":"c","type":"formula","formula":{"type":"boolean","boolean":true}},
+		"Due":{"id":"d","type":"formula","formula":{"type":"date","date":{"start":"2022-05-01"}}},
+		"Empty":{"id":"e","type":"formula","formula":{"type":"string","string":null}}
+	}}`
+
+	p := database.Page{}
+	require.NoError(t, json.Unmarshal([]byte(raw), &p))
+	assert.Len(t, p.Raw, 5)
+
+	total := database.NewFormula(p.Raw["Total"])
+	assert.Equal(t, database.FormulaNumber, total.Type)
+	assert.Equal(t, 16777217.0, total.Number)
+	assert.Equal(t, 16777217.0, total.Value())
+
+	assert.Equal(t, "big", database.NewFormula(p.Raw["Label"]).String)
+	assert.True(t, database.NewFormula(p.Raw["Late"]).Boolean)
+	assert.Equal(t, database.DateValue{Start: time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC), AllDay: true},
+		database.NewFormula(p.Raw["Due"]).Date)
+	assert.Equal(t, database.Formula{Type: database.FormulaString}, database.NewFormula(p.Raw["Empty"]))
+	assert.Equal(t, database.Formula{}, database.NewFormula(nil))
+
+	b, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"formula":{"type":"number","number":16777217}`)
+
+	got := database.Page{}
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, p.Raw, got.Raw)
+
+	for _, key := range []string{"Total", "Label", "Late", "Due"} {
+		f := database.NewFormula(p.Raw[key])
+
+		b, err := json.Marshal(f)
+		require.NoError(t, err)
+		assert.Equal(t, f, database.NewFormula(b), key)
+	}
+}
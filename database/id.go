@@ -0,0 +1,75 @@
+package database
+
+import "github.com/faetools/go-notion/pkg/notion"
+
+// ByID returns the property values, exact numbers and raw values of a page keyed by the names
+// the properties have in the schema, finding them by the given IDs, which are given by name.
+//
+// Properties that were renamed in Notion are found this way.
+// Values whose ID is not given keep their key, unless another value was found for it by ID.
+func ByID(ids map[string]string, props notion.PropertyValueMap, nums Numbers, raw RawValues,
+) (notion.PropertyValueMap, Numbers, RawValues) {
+	if len(ids) == 0 {
+		return props, nums, raw
+	}
+
+	names := make(map[string]string, len(ids))
+	for name, id := range ids {
+		names[id] = name
+	}
+
+	// the keys of the values, by the names they get
+	keys := make(map[string]string, len(props))
+
+	for key, v := range props {
+		if name, ok := names[v.Id]; ok && v.Id != "" {
+			keys[name] = key
+		}
+	}
+
+	for key, v := range props {
+		if _, ok := names[v.Id]; ok && v.Id != "" {
+			continue
+		}
+
+		if _, ok := keys[key]; !ok {
+			keys[key] = key
+		}
+	}
+
+	resProps := make(notion.PropertyValueMap, len(keys))
+	resNums, resRaw := Numbers{}, RawValues{}
+
+	for name, key := range keys {
+		resProps[name] = props[key]
+
+		if n, ok := nums[key]; ok {
+			resNums[name] = n
+		}
+
+		if r, ok := raw[key]; ok {
+			resRaw[name] = r
+		}
+	}
+
+	return resProps, resNums, resRaw
+}
+
+// toIDs returns the values keyed by the IDs of their properties, where they are given by name.
+func toIDs[T any](ids map[string]string, values map[string]T) map[string]T {
+	if len(ids) == 0 {
+		return values
+	}
+
+	res := make(map[string]T, len(values))
+
+	for key, v := range values {
+		if id, ok := ids[key]; ok && id != "" {
+			key = id
+		}
+
+		res[key] = v
+	}
+
+	return res
+}
@@ -0,0 +1,105 @@
+package database_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/faetools/client"
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestByID(t *testing.T) {
+	t.Parallel()
+
+	p := database.Page{}
+	require.NoError(t, json.Unmarshal([]byte(`{"object":"page","id":"page","properties":{
+		"Renamed":{"id":"n","type":"number","number":1.10},
+		"Stage":{"id":"other","type":"status","status":{"name":"Done"}},
+		"Stage (new)":{"id":"s","type":"status","status":{"name":"Lost"}},
+		"Notes":{"id":"x","type":"rich_text","rich_text":[]}
+	}}`), &p))
+
+	ids := map[string]string{"Amount": "n", "Stage": "s", "Missing": "m"}
+
+	props, nums, raw := database.ByID(ids, p.Properties, p.Numbers, p.Raw)
+	assert.ElementsMatch(t, []string{"Amount", "Stage", "Notes"}, keys(props))
+	assert.Equal(t, database.Numbers{"Amount": "1.10"}, nums)
+	assert.Equal(t, "Lost", database.NewStatus(raw["Stage"]).Name)
+	assert.Equal(t, "x", props["Notes"].Id)
+
+	props, nums, raw = database.ByID(nil, p.Properties, p.Numbers, p.Raw)
+	assert.Equal(t, p.Properties, props)
+	assert.Equal(t, p.Numbers, nums)
+	assert.Equal(t, p.Raw, raw)
+}
+
+func TestPropertyIDs(t *testing.T) {
+	t.Parallel()
+
+	var body struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(notion.Page{
+				Id: "page", LastEditedTime: time.Date(2022, 1, 1, 10, 1, 0, 0, time.UTC),
+				Properties: notion.PropertyValueMap{"Renamed": {Id: "d", Type: notion.PropertyTypeCheckbox}},
+			})
+
+			return
+		}
+
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_ = json.NewEncoder(w).Encode(notion.Page{Id: "page"})
+	}))
+	defer srv.Close()
+
+	cli, err := notion.NewDefaultClient("secret", client.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	done := true
+	props := notion.PropertyValueMap{
+		"Done":  {Type: notion.PropertyTypeCheckbox, Checkbox: &done},
+		"Other": {Type: notion.PropertyTypeCheckbox, Checkbox: &done},
+	}
+	ids := database.PropertyIDs(map[string]string{"Done": "d"})
+
+	_, err = database.CreatePage(ctx, cli, "db", props, ids)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"d", "Other"}, keys(body.Properties))
+
+	_, err = database.UpdatePage(ctx, cli, "page", props, ids)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"d", "Other"}, keys(body.Properties))
+
+	// changes to renamed properties are conflicts
+	loaded := database.Page{Page: notion.Page{
+		Id: "page", LastEditedTime: time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC),
+		Properties: notion.PropertyValueMap{"Done": {Id: "d", Type: notion.PropertyTypeCheckbox, Checkbox: &done}},
+	}}
+
+	_, err = database.UpdatePageIfUnchanged(ctx, cli, loaded, props, ids, database.MergeChanges())
+	conflict := &database.ConflictError{}
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, []string{"Done"}, conflict.Properties)
+}
+
+func keys[T any](m map[string]T) []string {
+	res := make([]string, 0, len(m))
+	for key := range m {
+		res = append(res, key)
+	}
+
+	return res
+}
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+const maxPageSize = 100
+
+// ErrDone is returned by an iterator when there are no more entries.
+var ErrDone = errors.New("no more entries")
+
+// Iterator iterates over the entries of a database query.
+//
+// Only one page of results is held at a time and the next one is only requested when needed.
+type Iterator[T any] struct {
+	fetch func(ctx context.Context, cursor *notion.UUID) ([]T, *notion.UUID, error)
+
+	results []T
+	cursor  *notion.UUID
+	started bool
+}
+
+// NewIterator returns an iterator over the database entries that match the filter,
+// in the order given by the sorts.
+func NewIterator[T any](cli *notion.Client, id notion.Id, filter *notion.Filter, sorts *notion.Sorts,
+	newEntry func(notion.Page) T,
+) *Iterator[T] {
+	return NewExactIterator(cli, id, filter, sorts, func(p Page) T { return newEntry(p.Page) })
+}
+
+// NewExactIterator is like NewIterator, but makes entries of pages with their exact numbers.
+func NewExactIterator[T any](cli *notion.Client, id notion.Id, filter *notion.Filter, sorts *notion.Sorts,
+	newEntry func(Page) T,
+) *Iterator[T] {
+	return &Iterator[T]{fetch: func(ctx context.Context, cursor *notion.UUID) ([]T, *notion.UUID, error) {
+		list, err := queryDatabase(ctx, cli, id, notion.DatabaseQuery{
+			Filter:      filter,
+			PageSize:    maxPageSize,
+			Sorts:       sorts,
+			StartCursor: cursor,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		entries := make([]T, len(list.Results))
+		for i, p := range list.Results {
+			entries[i] = newEntry(p)
+		}
+
+		if !list.HasMore {
+			return entries, nil, nil
+		}
+
+		// querying without a cursor would start over again and again
+		if list.NextCursor == "" {
+			return nil, nil, fmt.Errorf("querying database %s: more results without a cursor to get them", id)
+		}
+
+		return entries, (*notion.UUID)(&list.NextCursor), nil
+	}}
+}
+
+// NewSliceIterator returns an iterator over the given entries.
+func NewSliceIterator[T any](entries []T) *Iterator[T] {
+	return &Iterator[T]{fetch: func(context.Context, *notion.UUID) ([]T, *notion.UUID, error) {
+		return entries, nil, nil
+	}}
+}
+
+// Next returns the next entry or ErrDone if there are no more entries.
+//
+// To stop early, simply stop calling Next.
+func (it *Iterator[T]) Next(ctx context.Context) (T, error) {
+	var zero T
+
+	for len(it.results) == 0 {
+		if it.started && it.cursor == nil {
+			return zero, ErrDone
+		}
+
+		results, cursor, err := it.fetch(ctx, it.cursor)
+		if err != nil {
+			return zero, err
+		}
+
+		it.started = true
+		it.results, it.cursor = results, cursor
+	}
+
+	e := it.results[0]
+	it.results = it.results[1:]
+
+	return e, nil
+}
+
+// pagesList is a page of query results.
+type pagesList struct {
+	Results    []Page `json:"results"`
+	NextCursor string `json:"next_cursor"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// queryDatabase requests one page of results.
+func queryDatabase(ctx context.Context, cli *notion.Client, id notion.Id, q notion.DatabaseQuery,
+) (*pagesList, error) {
+	b, err := do(ctx, cli, http.MethodPost, "./v1/databases/"+url.PathEscape(string(id))+"/query", q)
+	if err != nil {
+		return nil, fmt.Errorf("querying database %s: %w", id, err)
+	}
+
+	list := &pagesList{}
+	if err := json.Unmarshal(b, list); err != nil {
+		return nil, fmt.Errorf("decoding results of querying database %s: %w", id, err)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,98 @@
+package database_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/faetools/client"
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterator(t *testing.T) {
+	t.Parallel()
+
+	queries := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries++
+
+		var q notion.DatabaseQuery
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&q))
+		assert.Equal(t, "/v1/databases/db/query", r.URL.Path)
+
+		// every page of results holds two pages
+		start := 0
+		if q.StartCursor != nil {
+			start, _ = strconv.Atoi(string(*q.StartCursor))
+		}
+
+		list := notion.PagesList{
+			Results:    notion.Pages{{Id: notion.UUID(strconv.Itoa(start))}, {Id: notion.UUID(strconv.Itoa(start + 1))}},
+			HasMore:    start < 4,
+			NextCursor: strconv.Itoa(start + 2),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(list)
+	}))
+	defer srv.Close()
+
+	cli, err := notion.NewDefaultClient("secret", client.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	newEntry := func(p notion.Page) notion.UUID { return p.Id }
+
+	it := database.NewIterator(cli, "db", nil, nil, newEntry)
+
+	ids := []notion.UUID{}
+
+	for {
+		id, err := it.Next(ctx)
+		if errors.Is(err, database.ErrDone) {
+			break
+		}
+
+		require.NoError(t, err)
+
+		ids = append(ids, id)
+	}
+
+	assert.Equal(t, []notion.UUID{"0", "1", "2", "3", "4", "5"}, ids)
+	assert.Equal(t, 3, queries)
+
+	// stopping early does not query any further
+	queries = 0
+	it = database.NewIterator(cli, "db", nil, nil, newEntry)
+
+	id, err := it.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, notion.UUID("0"), id)
+	assert.Equal(t, 1, queries)
+}
+
+func TestIterator_NoCursor(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(notion.PagesList{Results: notion.Pages{{Id: "0"}}, HasMore: true})
+	}))
+	defer srv.Close()
+
+	cli, err := notion.NewDefaultClient("secret", client.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	it := database.NewIterator(cli, "db", nil, nil, func(p notion.Page) notion.UUID { return p.Id })
+
+	_, err = it.Next(context.Background())
+	assert.EqualError(t, err, "querying database db: more results without a cursor to get them")
+}
@@ -0,0 +1,181 @@
+package database
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// Currency is the ISO 4217 code of a currency.
+type Currency string
+
+type currency struct {
+	format notion.NumberConfigFormat
+	code   Currency
+	symbol string
+	// digits is the number of digits of the minor unit.
+	digits int
+}
+
+// currencies are the currencies of all number formats Notion offers.
+var currencies = []currency{
+	{notion.NumberConfigFormatBaht, "THB", "฿", 2},
+	{notion.NumberConfigFormatCanadianDollar, "CAD", "CA$", 2},
+	{notion.NumberConfigFormatChileanPeso, "CLP", "CLP$", 0},
+	{notion.NumberConfigFormatColombianPeso, "COP", "COP$", 2},
+	{notion.NumberConfigFormatDanishKrone, "DKK", "kr", 2},
+	{notion.NumberConfigFormatDirham, "AED", "AED", 2},
+	{notion.NumberConfigFormatDollar, "USD", "$", 2},
+	{notion.NumberConfigFormatEuro, "EUR", "€", 2},
+	{notion.NumberConfigFormatForint, "HUF", "Ft", 2},
+	{notion.NumberConfigFormatFranc, "CHF", "CHF", 2},
+	{notion.NumberConfigFormatHongKongDollar, "HKD", "HK$", 2},
+	{notion.NumberConfigFormatKoruna, "CZK", "Kč", 2},
+	{notion.NumberConfigFormatKrona, "SEK", "kr", 2},
+	{notion.NumberConfigFormatLeu, "RON", "lei", 2},
+	{notion.NumberConfigFormatLira, "TRY", "₺", 2},
+	{notion.NumberConfigFormatMexicanPeso, "MXN", "MX$", 2},
+	{notion.NumberConfigFormatNewTaiwanDollar, "TWD", "NT$", 2},
+	{notion.NumberConfigFormatNewZealandDollar, "NZD", "NZ$", 2},
+	{notion.NumberConfigFormatNorwegianKrone, "NOK", "kr", 2},
+	{notion.NumberConfigFormatPhilippinePeso, "PHP", "₱", 2},
+	{notion.NumberConfigFormatPound, "GBP", "£", 2},
+	{notion.NumberConfigFormatRand, "ZAR", "R", 2},
+	{notion.NumberConfigFormatReal, "BRL", "R$", 2},
+	{notion.NumberConfigFormatRinggit, "MYR", "RM", 2},
+	{notion.NumberConfigFormatRiyal, "SAR", "SAR", 2},
+	{notion.NumberConfigFormatRuble, "RUB", "₽", 2},
+	{notion.NumberConfigFormatRupee, "INR", "₹", 2},
+	{notion.NumberConfigFormatRupiah, "IDR", "Rp", 2},
+	{notion.NumberConfigFormatShekel, "ILS", "₪", 2},
+	{notion.NumberConfigFormatWon, "KRW", "₩", 0},
+	{notion.NumberConfigFormatYen, "JPY", "¥", 0},
+	{notion.NumberConfigFormatYuan, "CNY", "CN¥", 2},
+	{notion.NumberConfigFormatZloty, "PLN", "zł", 2},
+}
+
+// CurrencyOf returns the currency of numbers with the given format, if they are amounts of money.
+func CurrencyOf(f notion.NumberConfigFormat) (Currency, bool) {
+	for _, c := range currencies {
+		if c.format == f {
+			return c.code, true
+		}
+	}
+
+	return "", false
+}
+
+func (c Currency) info() currency {
+	for _, info := range currencies {
+		if info.code == c {
+			return info
+		}
+	}
+
+	return currency{code: c, symbol: string(c), digits: 2}
+}
+
+// Money is an amount of money in the minor unit of its currency, e.g. cents.
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+// NewMoney returns the amount of money, rounded to the minor unit of the currency.
+func NewMoney[T ~float32 | ~float64](n T, c Currency) Money {
+	scale := math.Pow10(c.info().digits)
+	return Money{Amount: int64(math.Round(float64(n) * scale)), Currency: c}
+}
+
+// MoneyOf returns the amount of money of the property value, rounded to the minor unit of the currency.
+// It is exact if the JSON number of the property value is given.
+func MoneyOf(v notion.PropertyValue, n json.Number, c Currency) Money {
+	r, ok := new(big.Rat).SetString(string(n))
+	if n == "" || !ok {
+		return NewMoney(v.GetNumber(), c)
+	}
+
+	r.Mul(r, new(big.Rat).SetInt(pow10(c.info().digits)))
+
+	return Money{Amount: round(r), Currency: c}
+}
+
+// Number returns the exact JSON number of the amount in the major unit of the currency.
+func (m Money) Number() json.Number {
+	digits := m.Currency.info().digits
+	return json.Number(new(big.Rat).SetFrac(big.NewInt(m.Amount), pow10(digits)).FloatString(digits))
+}
+
+// pow10 returns 10 to the power of n.
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// round rounds the rational number to the nearest integer, rounding halves away from zero.
+func round(r *big.Rat) int64 {
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+
+	if m.Abs(m).Lsh(m, 1).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(int64(r.Sign())))
+	}
+
+	return q.Int64()
+}
+
+// Float returns the amount in the major unit of the currency, as Notion holds it.
+func (m Money) Float() float64 {
+	return float64(m.Amount) / math.Pow10(m.Currency.info().digits)
+}
+
+// String returns the amount the way Notion displays it, e.g. $1,234.50.
+func (m Money) String() string {
+	info := m.Currency.info()
+	amount := m.Amount
+
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	s := strconv.FormatInt(amount, 10)
+	if len(s) <= info.digits {
+		s = strings.Repeat("0", info.digits-len(s)+1) + s
+	}
+
+	whole, minor := s[:len(s)-info.digits], s[len(s)-info.digits:]
+	if minor != "" {
+		minor = "." + minor
+	}
+
+	return sign + info.symbol + withCommas(whole) + minor
+}
+
+// Percent is a number displayed as percentage, i.e. 0.5 is displayed as 50%.
+type Percent float64
+
+// String returns the percentage the way Notion displays it, e.g. 50%.
+func (p Percent) String() string {
+	// avoid showing floating point errors like 56.99999999999999%
+	rounded := math.Round(float64(p)*1e8) / 1e6
+
+	return strconv.FormatFloat(rounded, 'f', -1, 64) + "%"
+}
+
+// withCommas separates the thousands of the digits with commas.
+func withCommas(digits string) string {
+	b := &strings.Builder{}
+
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+
+		b.WriteRune(d)
+	}
+
+	return b.String()
+}
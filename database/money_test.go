@@ -0,0 +1,65 @@
+package database_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoney(t *testing.T) {
+	t.Parallel()
+
+	c, ok := database.CurrencyOf(notion.NumberConfigFormatDollar)
+	assert.True(t, ok)
+	assert.Equal(t, database.Currency("USD"), c)
+
+	_, ok = database.CurrencyOf(notion.NumberConfigFormatPercent)
+	assert.False(t, ok)
+
+	m := database.NewMoney(1234.505, "USD")
+	assert.Equal(t, database.Money{Amount: 123451, Currency: "USD"}, m)
+	assert.Equal(t, 1234.51, m.Float())
+
+	for want, m := range map[string]database.Money{
+		"$1,234.51":      m,
+		"$0.05":          {Amount: 5, Currency: "USD"},
+		"-€1,000,000.00": {Amount: -100_000_000, Currency: "EUR"},
+		"¥1,235":         database.NewMoney(1234.5, "JPY"),
+		"XYZ1.00":        {Amount: 100, Currency: "XYZ"},
+	} {
+		assert.Equal(t, want, m.String())
+	}
+}
+
+func TestMoneyOf(t *testing.T) {
+	t.Parallel()
+
+	// a float32 can't hold these amounts
+	m := database.MoneyOf(notion.PropertyValue{}, "1234567.89", "USD")
+	assert.Equal(t, database.Money{Amount: 123456789, Currency: "USD"}, m)
+	assert.Equal(t, "$1,234,567.89", m.String())
+	assert.Equal(t, json.Number("1234567.89"), m.Number())
+
+	assert.Equal(t, int64(123456790), database.MoneyOf(notion.PropertyValue{}, "1234567.895", "USD").Amount)
+	assert.Equal(t, int64(-123456790), database.MoneyOf(notion.PropertyValue{}, "-1234567.895", "USD").Amount)
+	assert.Equal(t, int64(1235), database.MoneyOf(notion.PropertyValue{}, "1.2345e3", "JPY").Amount)
+	assert.Equal(t, json.Number("1235"), database.Money{Amount: 1235, Currency: "JPY"}.Number())
+	assert.Equal(t, json.Number("-0.05"), database.Money{Amount: -5, Currency: "EUR"}.Number())
+
+	// without the JSON number, the float32 is used
+	n := float32(12.5)
+	assert.Equal(t, int64(1250), database.MoneyOf(notion.PropertyValue{Number: &n}, "", "EUR").Amount)
+	assert.Equal(t, int64(0), database.MoneyOf(notion.PropertyValue{}, "", "EUR").Amount)
+}
+
+func TestPercent(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "50%", database.Percent(0.5).String())
+	assert.Equal(t, "57%", database.Percent(0.57).String())
+	assert.Equal(t, "12.5%", database.Percent(0.125).String())
+	assert.Equal(t, "-200%", database.Percent(-2).String())
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// Numbers are the numbers of property values as they are sent as JSON, by property name.
+//
+// notion.PropertyValue holds numbers as float32, which can't hold all numbers exactly.
+type Numbers map[string]json.Number
+
+// Float64 returns the number of the property value, exactly if its JSON number is given.
+func Float64(v notion.PropertyValue, n json.Number) float64 {
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+
+	return float64(v.GetNumber())
+}
+
+// Int64 returns the number of the property value as integer, exactly if its JSON number is given.
+func Int64(v notion.PropertyValue, n json.Number) int64 {
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+
+	if f, err := n.Float64(); err == nil {
+		return int64(f)
+	}
+
+	return int64(v.GetNumber())
+}
+
+// DecimalOf returns the number of the property value as decimal, exactly if its JSON number is given.
+func DecimalOf(v notion.PropertyValue, n json.Number) Decimal {
+	if n != "" {
+		return Decimal(n)
+	}
+
+	if v.Number == nil {
+		return ""
+	}
+
+	return Decimal(strconv.FormatFloat(float64(*v.Number), 'f', -1, 32))
+}
+
+// Float64Number returns the JSON number of the float.
+func Float64Number(f float64) json.Number {
+	return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// Int64Number returns the JSON number of the integer.
+func Int64Number(i int64) json.Number {
+	return json.Number(strconv.FormatInt(i, 10))
+}
+
+// Decimal is an exact decimal number, written the way JSON writes numbers, e.g. 0.1 or 1e-7.
+// The empty decimal is zero.
+type Decimal string
+
+// Rat returns the decimal as a rational number.
+func (d Decimal) Rat() (*big.Rat, bool) {
+	if d == "" {
+		return new(big.Rat), true
+	}
+
+	return new(big.Rat).SetString(string(d))
+}
+
+// Float64 returns the nearest float to the decimal.
+func (d Decimal) Float64() float64 {
+	r, ok := d.Rat()
+	if !ok {
+		return 0
+	}
+
+	f, _ := r.Float64()
+
+	return f
+}
+
+// Number returns the decimal as a JSON number.
+func (d Decimal) Number() json.Number {
+	if d == "" {
+		return "0"
+	}
+
+	return json.Number(d)
+}
+
+func (d Decimal) String() string {
+	if d == "" {
+		return "0"
+	}
+
+	return string(d)
+}
@@ -0,0 +1,74 @@
+package database_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPage(t *testing.T) {
+	t.Parallel()
+
+	const raw = `{"object":"page","id":"page","properties":{
+		"Views":{"type":"number","number":16777217},
+		"Price":{"type":"number","number":0.1},
+		"Empty":{"type":"number","number":null},
+		"Done":{"type":"checkbox","checkbox":true}
+	}}`
+
+	p := database.Page{}
+	require.NoError(t, json.Unmarshal([]byte(raw), &p))
+
+	assert.Equal(t, database.Numbers{"Views": "16777217", "Price": "0.1"}, p.Numbers)
+	assert.True(t, p.Properties["Done"].GetCheckbox())
+
+	assert.Equal(t, int64(16777217), database.Int64(p.Properties["Views"], p.Numbers["Views"]))
+	assert.Equal(t, 0.1, database.Float64(p.Properties["Price"], p.Numbers["Price"]))
+	assert.Equal(t, database.Decimal("0.1"), database.DecimalOf(p.Properties["Price"], p.Numbers["Price"]))
+	assert.Equal(t, database.Decimal(""), database.DecimalOf(p.Properties["Empty"], p.Numbers["Empty"]))
+
+	// without the exact number, the float32 is used
+	assert.Equal(t, int64(16777216), database.Int64(p.Properties["Views"], ""))
+	assert.Equal(t, database.Decimal("0.1"), database.DecimalOf(p.Properties["Price"], ""))
+
+	b, err := json.Marshal(p)
+	require.NoError(t, err)
+
+	got := database.Page{}
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, p.Numbers, got.Numbers)
+}
+
+func TestDecimal(t *testing.T) {
+	t.Parallel()
+
+	r, ok := database.Decimal("0.1").Rat()
+	assert.True(t, ok)
+	assert.Equal(t, big.NewRat(1, 10), r)
+
+	assert.Equal(t, 0.1, database.Decimal("0.1").Float64())
+	assert.Equal(t, json.Number("0"), database.Decimal("").Number())
+	assert.Equal(t, "1e-7", database.Decimal("1e-7").String())
+
+	assert.Equal(t, json.Number("16777217"), database.Int64Number(16777217))
+	assert.Equal(t, json.Number("0.1"), database.Float64Number(0.1))
+}
+
+func TestDiffExact(t *testing.T) {
+	t.Parallel()
+
+	n := float32(16777216)
+	v := notion.PropertyValue{Type: notion.PropertyTypeNumber, Number: &n}
+	props := notion.PropertyValueMap{"Views": v}
+
+	assert.Empty(t, database.DiffExact(props, props,
+		database.Numbers{"Views": "16777216"}, database.Numbers{"Views": "16777216.0"}))
+
+	assert.Equal(t, props, database.DiffExact(props, props,
+		database.Numbers{"Views": "16777216"}, database.Numbers{"Views": "16777217"}))
+}
@@ -0,0 +1,402 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/google/uuid"
+)
+
+// Page is a page together with the exact numbers of its property values
+// and the values notion.PropertyValue can't hold.
+//
+// Unlike notion.Page, it can be decoded from pages with all-day dates or dates without offset.
+type Page struct {
+	notion.Page
+	Numbers Numbers
+	Raw     RawValues
+}
+
+// RawValues are the values of properties that notion.PropertyValue can't hold,
+// e.g. the results of formulas, as they are sent as JSON, by property name.
+type RawValues map[string]json.RawMessage
+
+// Value is a property value together with its exact number
+// and its raw value, if notion.PropertyValue can't hold it.
+//
+// Unlike notion.PropertyValue, it can be decoded from all-day dates or dates without offset.
+type Value struct {
+	notion.PropertyValue
+	Exact json.Number
+	Raw   json.RawMessage
+}
+
+// UnmarshalJSON decodes the property value and keeps its number as it is.
+func (v *Value) UnmarshalJSON(b []byte) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	*v = Value{}
+
+	if t, ok := fields["type"]; ok {
+		if err := json.Unmarshal(t, &v.Type); err != nil {
+			return err
+		}
+	}
+
+	if n, ok := fields["number"]; ok {
+		var exact *json.Number
+		if err := json.Unmarshal(n, &exact); err != nil {
+			return err
+		}
+
+		if exact != nil {
+			v.Exact = *exact
+		}
+	}
+
+	if !holdsValue(v.Type) {
+		v.Raw = fields[string(v.Type)]
+	}
+
+	var date *notion.Date
+
+	if d, ok := fields["date"]; ok {
+		var raw *rawDate
+		if err := json.Unmarshal(d, &raw); err != nil {
+			return err
+		}
+
+		if raw != nil {
+			decoded, err := raw.decode()
+			if err != nil {
+				return err
+			}
+
+			date = &decoded
+		}
+
+		// notion.Date can't decode all dates
+		delete(fields, "date")
+
+		var err error
+		if b, err = json.Marshal(fields); err != nil {
+			return err
+		}
+	}
+
+	if err := json.Unmarshal(b, &v.PropertyValue); err != nil {
+		return err
+	}
+
+	v.Date = date
+
+	return nil
+}
+
+// MarshalJSON encodes the property value with its exact number and its raw value.
+func (v Value) MarshalJSON() ([]byte, error) {
+	return encodeValue(v.PropertyValue, v.Exact, v.Raw)
+}
+
+// holdsValue reports whether notion.PropertyValue can hold values of the type.
+func holdsValue(typ notion.PropertyType) bool {
+	switch typ {
+	case "",
+		notion.PropertyTypeTitle,
+		notion.PropertyTypeRichText,
+		notion.PropertyTypeNumber,
+		notion.PropertyTypeSelect,
+		notion.PropertyTypeMultiSelect,
+		notion.PropertyTypeDate,
+		notion.PropertyTypeCheckbox,
+		notion.PropertyTypeRelation,
+		notion.PropertyTypeFiles:
+		return true
+	default:
+		return false
+	}
+}
+
+// UnmarshalJSON decodes the page and keeps its numbers as they are.
+func (p *Page) UnmarshalJSON(b []byte) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	values := map[string]Value{}
+
+	if props, ok := fields["properties"]; ok {
+		if err := json.Unmarshal(props, &values); err != nil {
+			return err
+		}
+
+		// the property values are decoded on their own
+		delete(fields, "properties")
+
+		var err error
+		if b, err = json.Marshal(fields); err != nil {
+			return err
+		}
+	}
+
+	if err := json.Unmarshal(b, &p.Page); err != nil {
+		return err
+	}
+
+	p.Numbers, p.Raw = Numbers{}, RawValues{}
+
+	if values == nil {
+		return nil
+	}
+
+	p.Properties = make(notion.PropertyValueMap, len(values))
+
+	for key, v := range values {
+		p.Properties[key] = v.PropertyValue
+
+		if v.Exact != "" {
+			p.Numbers[key] = v.Exact
+		}
+
+		if v.Raw != nil {
+			p.Raw[key] = v.Raw
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the page with its exact numbers and raw values.
+func (p Page) MarshalJSON() ([]byte, error) {
+	props, err := encodeProperties(p.Properties, p.Numbers, p.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(p.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	m := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	m["properties"] = props
+
+	return json.Marshal(m)
+}
+
+// encodeProperties encodes the property values with the exact numbers and the raw values, where they are given.
+func encodeProperties(props notion.PropertyValueMap, nums Numbers, raw RawValues) (json.RawMessage, error) {
+	m := make(map[string]json.RawMessage, len(props))
+
+	for key, v := range props {
+		b, err := encodeValue(v, nums[key], raw[key])
+		if err != nil {
+			return nil, fmt.Errorf("encoding %q: %w", key, err)
+		}
+
+		m[key] = b
+	}
+
+	return json.Marshal(m)
+}
+
+// encodeValue encodes the property value with the exact number or the raw value, if it is given,
+// and its date the way Notion expects it.
+//
+// Values that were cleared are sent as empty values, since Notion leaves properties alone
+// whose value is missing. Values without ID are sent without one.
+func encodeValue(v notion.PropertyValue, n json.Number, raw json.RawMessage) (json.RawMessage, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	if v.Id == "" {
+		delete(fields, "id")
+	}
+
+	if n != "" && v.Number != nil {
+		fields["number"] = json.RawMessage(n)
+	}
+
+	if raw != nil && !holdsValue(v.Type) {
+		fields[string(v.Type)] = raw
+	}
+
+	if v.Date != nil {
+		if fields["date"], err = encodeDate(*v.Date); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, ok := fields[string(v.Type)]; !ok && v.Type != "" {
+		fields[string(v.Type)] = emptyValue(v.Type)
+	}
+
+	return json.Marshal(fields)
+}
+
+// emptyValue returns the value Notion expects for a property of the type that has been cleared.
+func emptyValue(typ notion.PropertyType) json.RawMessage {
+	switch typ {
+	case notion.PropertyTypeTitle, notion.PropertyTypeRichText, notion.PropertyTypeMultiSelect,
+		notion.PropertyTypeRelation, notion.PropertyTypeFiles, notion.PropertyTypePeople:
+		return json.RawMessage("[]")
+	default:
+		return json.RawMessage("null")
+	}
+}
+
+type databaseParent struct {
+	DatabaseID notion.Id `json:"database_id"`
+}
+
+type createPageBody struct {
+	Parent     databaseParent  `json:"parent"`
+	Properties json.RawMessage `json:"properties"`
+}
+
+type updatePageBody struct {
+	Properties json.RawMessage `json:"properties,omitempty"`
+	Archived   *bool           `json:"archived,omitempty"`
+}
+
+type updateOptions struct {
+	merge   bool
+	numbers Numbers
+	raw     RawValues
+	ids     map[string]string
+}
+
+func newUpdateOptions(opts []UpdateOption) *updateOptions {
+	o := &updateOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// UpdateOption sets an option for creating or updating a page.
+type UpdateOption func(*updateOptions)
+
+// ExactNumbers sends the given numbers instead of the float32 numbers of the property values.
+func ExactNumbers(nums Numbers) UpdateOption {
+	return func(o *updateOptions) { o.numbers = nums }
+}
+
+// RawProperties sends the given raw values for the property values notion.PropertyValue can't hold,
+// e.g. statuses.
+func RawProperties(raw RawValues) UpdateOption {
+	return func(o *updateOptions) { o.raw = raw }
+}
+
+// PropertyIDs addresses the properties by the given IDs instead of their names,
+// so that updates still work after the properties were renamed.
+// The IDs are given by property name; properties without ID are addressed by name.
+func PropertyIDs(ids map[string]string) UpdateOption {
+	return func(o *updateOptions) { o.ids = ids }
+}
+
+// GetPage returns the page with the given ID.
+func GetPage(ctx context.Context, cli *notion.Client, id notion.Id) (*Page, error) {
+	b, err := do(ctx, cli, http.MethodGet, pagePath(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting page %s: %w", id, err)
+	}
+
+	return decodePage(b)
+}
+
+// CreatePage creates a page with the given property values in the database.
+func CreatePage(ctx context.Context, cli *notion.Client, dbID notion.Id, props notion.PropertyValueMap,
+	opts ...UpdateOption,
+) (*Page, error) {
+	o := newUpdateOptions(opts)
+
+	encoded, err := encodeProperties(toIDs(o.ids, props), toIDs(o.ids, o.numbers), toIDs(o.ids, o.raw))
+	if err != nil {
+		return nil, fmt.Errorf("encoding page: %w", err)
+	}
+
+	b, err := do(ctx, cli, http.MethodPost, "./v1/pages/", createPageBody{
+		Parent:     databaseParent{DatabaseID: dbID},
+		Properties: encoded,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating page in database %s: %w", dbID, err)
+	}
+
+	return decodePage(b)
+}
+
+// UpdatePage updates the given property values of the page.
+// Property values that are not given are left as they are.
+func UpdatePage(ctx context.Context, cli *notion.Client, id notion.Id, props notion.PropertyValueMap,
+	opts ...UpdateOption,
+) (*Page, error) {
+	o := newUpdateOptions(opts)
+
+	encoded, err := encodeProperties(toIDs(o.ids, props), toIDs(o.ids, o.numbers), toIDs(o.ids, o.raw))
+	if err != nil {
+		return nil, fmt.Errorf("encoding page: %w", err)
+	}
+
+	return updatePage(ctx, cli, id, updatePageBody{Properties: encoded})
+}
+
+// ArchivePage archives the page.
+func ArchivePage(ctx context.Context, cli *notion.Client, id notion.Id) (*Page, error) {
+	archived := true
+	return updatePage(ctx, cli, id, updatePageBody{Archived: &archived})
+}
+
+func updatePage(ctx context.Context, cli *notion.Client, id notion.Id, upd updatePageBody) (*Page, error) {
+	b, err := do(ctx, cli, http.MethodPatch, pagePath(id), upd)
+	if err != nil {
+		return nil, fmt.Errorf("updating page %s: %w", id, err)
+	}
+
+	return decodePage(b)
+}
+
+// decodePage decodes the page of a response.
+func decodePage(body []byte) (*Page, error) {
+	p := &Page{}
+	if err := json.Unmarshal(body, p); err != nil {
+		return nil, fmt.Errorf("decoding page: %w", err)
+	}
+
+	return p, nil
+}
+
+// NewPage returns a new page with a random ID and the given property values,
+// as Notion would create it.
+func NewPage(props notion.PropertyValueMap) notion.Page {
+	now := time.Now().UTC()
+
+	return notion.Page{
+		Object:         "page",
+		Id:             notion.UUID(uuid.NewString()),
+		CreatedTime:    &now,
+		LastEditedTime: now,
+		Properties:     props,
+	}
+}
@@ -0,0 +1,157 @@
+package database_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/faetools/client"
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPages(t *testing.T) {
+	t.Parallel()
+
+	var (
+		method string
+		body   map[string]interface{}
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method + " " + r.URL.Path
+		body = map[string]interface{}{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(notion.Page{Id: "page"})
+	}))
+	defer srv.Close()
+
+	cli, err := notion.NewDefaultClient("secret", client.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	done := true
+	props := notion.PropertyValueMap{"Done": {Type: notion.PropertyTypeCheckbox, Checkbox: &done}}
+
+	p, err := database.CreatePage(ctx, cli, "db", props)
+	require.NoError(t, err)
+	assert.Equal(t, notion.UUID("page"), p.Id)
+	assert.Equal(t, "POST /v1/pages/", method)
+	assert.Equal(t, map[string]interface{}{"database_id": "db"}, body["parent"])
+	assert.Contains(t, body, "properties")
+
+	_, err = database.UpdatePage(ctx, cli, "page", props)
+	require.NoError(t, err)
+	assert.Equal(t, "PATCH /v1/pages/page", method)
+	assert.NotContains(t, body, "archived")
+	assert.Contains(t, body, "properties")
+
+	_, err = database.ArchivePage(ctx, cli, "page")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"archived": true}, body)
+}
+
+func TestNewPage(t *testing.T) {
+	t.Parallel()
+
+	a, b := database.NewPage(nil), database.NewPage(nil)
+	assert.NotEqual(t, a.Id, b.Id)
+	assert.Equal(t, a.LastEditedTime, *a.CreatedTime)
+}
+
+func TestUpdatePage_Cleared(t *testing.T) {
+	t.Parallel()
+
+	var body json.RawMessage
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(notion.Page{Id: "page"})
+	}))
+	defer srv.Close()
+
+	cli, err := notion.NewDefaultClient("secret", client.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	from := notion.PropertyValueMap{
+		"Category": {Type: notion.PropertyTypeSelect, Select: database.SelectName("Work")},
+		"Due":      {Type: notion.PropertyTypeDate, Date: database.Time(time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC))},
+		"Tags":     {Type: notion.PropertyTypeMultiSelect, MultiSelect: database.Options([]string{"a"})},
+		"Name":     {Id: "title", Type: notion.PropertyTypeTitle, Title: database.Text("Hello")},
+	}
+	to := notion.PropertyValueMap{
+		"Category": {Type: notion.PropertyTypeSelect, Select: database.SelectName("")},
+		"Due":      {Type: notion.PropertyTypeDate, Date: database.Time(time.Time{})},
+		"Tags":     {Type: notion.PropertyTypeMultiSelect},
+		"Name":     {Id: "title", Type: notion.PropertyTypeTitle, Title: database.Text("Hello")},
+	}
+
+	_, err = database.UpdatePage(context.Background(), cli, "page", database.Diff(from, to))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"properties":{
+		"Category":{"type":"select","select":null},
+		"Due":{"type":"date","date":null},
+		"Tags":{"type":"multi_select","multi_select":[]}
+	}}`, string(body))
+
+	to["Name"] = notion.PropertyValue{Id: "title", Type: notion.PropertyTypeTitle, Title: database.Text("")}
+
+	_, err = database.UpdatePage(context.Background(), cli, "page", database.Diff(from, to))
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"Name":{"id":"title","title":[],"type":"title"}`)
+}
+
+func TestUpdatePage_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, status := range []int{
+		http.StatusUnauthorized, http.StatusForbidden, http.StatusConflict,
+		http.StatusInternalServerError, http.StatusServiceUnavailable,
+	} {
+		status := status
+
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				_ = json.NewEncoder(w).Encode(notion.Error{
+					Object: "error", Status: status, Code: "some_code", Message: "Something went wrong.",
+				})
+			}))
+			defer srv.Close()
+
+			cli, err := notion.NewDefaultClient("secret", client.WithBaseURL(srv.URL))
+			require.NoError(t, err)
+
+			_, err = database.UpdatePage(context.Background(), cli, "page", notion.PropertyValueMap{})
+
+			notionErr := &notion.Error{}
+			require.ErrorAs(t, err, &notionErr)
+			assert.Equal(t, status, notionErr.Status)
+			assert.Equal(t, "some_code", notionErr.Code)
+		})
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	cli, err := notion.NewDefaultClient("secret", client.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	_, err = database.UpdatePage(context.Background(), cli, "page", notion.PropertyValueMap{})
+	assert.ErrorContains(t, err, "unknown error response (502): bad gateway")
+}
@@ -0,0 +1,257 @@
+package database
+
+import (
+	"math"
+	"math/rand"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+var words = []string{
+	"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel",
+	"india", "juliett", "kilo", "lima", "mike", "november", "oscar", "papa",
+}
+
+type randomOptions struct {
+	from, to time.Time
+	maxWords int
+}
+
+// RandomOption sets an option for generating random property values.
+type RandomOption func(*randomOptions)
+
+// Between sets the range of random dates. The end is exclusive.
+func Between(from, to time.Time) RandomOption {
+	return func(o *randomOptions) { o.from, o.to = from, to }
+}
+
+// MaxWords sets how many words a random text has at most.
+func MaxWords(n int) RandomOption {
+	return func(o *randomOptions) { o.maxWords = n }
+}
+
+func newRandomOptions(opts []RandomOption) *randomOptions {
+	o := &randomOptions{
+		from:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		to:       time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		maxWords: 4,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// RandomText returns a text of at least one random word.
+func RandomText(r *rand.Rand, opts ...RandomOption) notion.RichTexts {
+	o := newRandomOptions(opts)
+
+	n := 1
+	if o.maxWords > 1 {
+		n += r.Intn(o.maxWords)
+	}
+
+	ws := make([]string, n)
+	for i := range ws {
+		ws[i] = words[r.Intn(len(words))]
+	}
+
+	return notion.NewRichTexts(strings.Join(ws, " "))
+}
+
+// Truncate returns the text cut off after n characters.
+func Truncate(t notion.RichTexts, n int) notion.RichTexts {
+	content := t.Content()
+	if utf8.RuneCountInString(content) <= n {
+		return t
+	}
+
+	return notion.NewRichTexts(string([]rune(content)[:n]))
+}
+
+// RandomMatch returns a random non-empty text that matches the pattern
+// and has at most maxLength characters, unless maxLength is 0.
+// It returns an empty text if it doesn't find one.
+func RandomMatch(r *rand.Rand, pattern *regexp.Regexp, maxLength int) notion.RichTexts {
+	re, err := syntax.Parse(pattern.String(), syntax.Perl)
+	if err != nil {
+		return notion.RichTexts{}
+	}
+
+	re = re.Simplify()
+
+	for i := 0; i < 100; i++ {
+		b := &strings.Builder{}
+		writeMatch(r, b, re)
+
+		text := b.String()
+		if text != "" && (maxLength == 0 || utf8.RuneCountInString(text) <= maxLength) &&
+			pattern.MatchString(text) {
+			return notion.NewRichTexts(text)
+		}
+	}
+
+	return notion.RichTexts{}
+}
+
+// writeMatch writes a random text matching the regular expression, ignoring anchors and word boundaries.
+func writeMatch(r *rand.Rand, b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		b.WriteRune(randomRune(r, re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteRune(rune('a' + r.Intn(26)))
+	case syntax.OpCapture:
+		writeMatch(r, b, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writeMatch(r, b, sub)
+		}
+	case syntax.OpAlternate:
+		writeMatch(r, b, re.Sub[r.Intn(len(re.Sub))])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		min, max := 0, 3
+
+		switch re.Op {
+		case syntax.OpPlus:
+			min = 1
+		case syntax.OpQuest:
+			max = 1
+		case syntax.OpRepeat:
+			min, max = re.Min, re.Max
+			if max < 0 {
+				max = min + 3
+			}
+		}
+
+		for n := min + r.Intn(max-min+1); n > 0; n-- {
+			writeMatch(r, b, re.Sub[0])
+		}
+	}
+}
+
+// randomRune returns a random rune of the ranges of a character class,
+// preferring printable ASCII characters.
+func randomRune(r *rand.Rand, ranges []rune) rune {
+	printable := []rune{}
+
+	for i := 0; i < len(ranges); i += 2 {
+		for c := ranges[i]; c <= ranges[i+1] && c <= '~'; c++ {
+			if c >= ' ' {
+				printable = append(printable, c)
+			}
+		}
+	}
+
+	if len(printable) == 0 {
+		return ranges[0]
+	}
+
+	return printable[r.Intn(len(printable))]
+}
+
+// RandomNumber returns a random number with two decimals between 0 and 1000.
+func RandomNumber(r *rand.Rand) float32 {
+	return float32(r.Intn(100_000)) / 100
+}
+
+// RandomBetween returns a random number between min and max, inclusive, with at most the given number of decimals.
+// It returns min if there is no such number.
+func RandomBetween(r *rand.Rand, min, max float64, decimals int) float64 {
+	scale := math.Pow10(decimals)
+	lo, hi := math.Ceil(min*scale), math.Floor(max*scale)
+
+	if hi < lo {
+		return min
+	}
+
+	n := (lo + float64(r.Int63n(int64(hi-lo)+1))) / scale
+
+	return math.Max(min, math.Min(max, n))
+}
+
+// RandomNumberBetween returns a random number with two decimals between min and max, inclusive,
+// that stays within them when held as float32.
+func RandomNumberBetween(r *rand.Rand, min, max float64) float32 {
+	n := float32(RandomBetween(r, min, max, 2))
+
+	if float64(n) < min {
+		n = math.Nextafter32(n, float32(math.Inf(1)))
+	}
+
+	if float64(n) > max {
+		n = math.Nextafter32(n, float32(math.Inf(-1)))
+	}
+
+	return n
+}
+
+// RandomSelect returns one of the options with the given names,
+// or no option if there are none.
+func RandomSelect(r *rand.Rand, names ...string) notion.SelectValue {
+	if len(names) == 0 {
+		return notion.SelectValue{}
+	}
+
+	return notion.SelectValue{Name: names[r.Intn(len(names))]}
+}
+
+// RandomMultiSelect returns some of the options with the given names, in the given order.
+func RandomMultiSelect(r *rand.Rand, names ...string) notion.PropertyOptions {
+	opts := notion.PropertyOptions{}
+
+	for _, name := range names {
+		if r.Intn(2) == 1 {
+			opts = append(opts, notion.PropertyOption{Name: name})
+		}
+	}
+
+	return opts
+}
+
+// RandomNonEmptyMultiSelect is like RandomMultiSelect, but returns at least one option if there are any.
+func RandomNonEmptyMultiSelect(r *rand.Rand, names ...string) notion.PropertyOptions {
+	opts := RandomMultiSelect(r, names...)
+	if len(opts) > 0 || len(names) == 0 {
+		return opts
+	}
+
+	return notion.PropertyOptions{{Name: names[r.Intn(len(names))]}}
+}
+
+// RandomDate returns a random all-day date within the range given by the options.
+func RandomDate(r *rand.Rand, opts ...RandomOption) DateValue {
+	o := newRandomOptions(opts)
+
+	days := int(o.to.Sub(o.from).Hours() / 24)
+	if days < 1 {
+		return DateValue{Start: day(o.from, time.UTC), AllDay: true}
+	}
+
+	return DateValue{Start: day(o.from.AddDate(0, 0, r.Intn(days)), time.UTC), AllDay: true}
+}
+
+// RandomMoney returns a random amount of money between 0 and 1000.
+func RandomMoney(r *rand.Rand, c Currency) Money {
+	return NewMoney(RandomNumber(r), c)
+}
+
+// RandomPercent returns a random whole percentage between 0% and 100%.
+func RandomPercent(r *rand.Rand) Percent {
+	return Percent(float32(r.Intn(101)) / 100)
+}
+
+// RandomDecimal returns a random decimal with two decimals between 0 and 1000.
+func RandomDecimal(r *rand.Rand) Decimal {
+	return Decimal(strconv.FormatFloat(float64(r.Intn(100_000))/100, 'f', -1, 64))
+}
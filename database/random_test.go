@@ -0,0 +1,79 @@
+package database_test
+
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandom(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+
+	for i := 0; i < 100; i++ {
+		d := database.RandomDate(r, database.Between(from, to))
+		assert.False(t, d.Start.Before(from))
+		assert.True(t, d.Start.Before(to))
+		assert.True(t, d.IsAllDay())
+
+		assert.Contains(t, []string{"a", "b"}, database.RandomSelect(r, "a", "b").Name)
+		assert.Empty(t, database.RandomSelect(r).Name)
+
+		for _, opt := range database.RandomMultiSelect(r, "a", "b") {
+			assert.Contains(t, []string{"a", "b"}, opt.Name)
+		}
+
+		assert.NotEmpty(t, database.RandomText(r, database.MaxWords(1)).Content())
+		assert.NotContains(t, database.RandomText(r, database.MaxWords(1)).Content(), " ")
+
+		n := database.RandomNumber(r)
+		assert.True(t, n >= 0 && n < 1000)
+
+		assert.NotEmpty(t, database.RandomNonEmptyMultiSelect(r, "a", "b"))
+		assert.Empty(t, database.RandomNonEmptyMultiSelect(r))
+
+		f := database.RandomBetween(r, 0.5, 2.5, 0)
+		assert.Contains(t, []float64{1, 2}, f)
+
+		n = database.RandomNumberBetween(r, 0.1, 0.3)
+		assert.True(t, float64(n) >= 0.1 && float64(n) <= 0.3, n)
+	}
+
+	// the same seed gives the same values
+	assert.Equal(t,
+		database.RandomText(rand.New(rand.NewSource(2))),
+		database.RandomText(rand.New(rand.NewSource(2))))
+}
+
+func TestRandomMatch(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	for _, pattern := range []*regexp.Regexp{
+		regexp.MustCompile(`^[A-Z][a-z]+( [a-z]+)*$`),
+		regexp.MustCompile(`^\d{3}-\d{4}$`),
+		regexp.MustCompile(`(?i)^(foo|bar)\.?$`),
+		regexp.MustCompile(`[^a-z]x*`),
+	} {
+		for i := 0; i < 100; i++ {
+			text := database.RandomMatch(r, pattern, 10).Content()
+			assert.Regexp(t, pattern, text)
+			assert.LessOrEqual(t, len(text), 10)
+		}
+	}
+
+	// no match that short
+	assert.Empty(t, database.RandomMatch(r, regexp.MustCompile(`^a{5}$`), 4).Content())
+
+	assert.Equal(t, "alph", database.Truncate(notion.NewRichTexts("alpha"), 4).Content())
+	assert.Equal(t, "alpha", database.Truncate(notion.NewRichTexts("alpha"), 5).Content())
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/faetools/client"
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// do sends a request to the Notion API and returns the body of the response.
+//
+// Unlike the methods of notion.Client, it doesn't decode the response into notion types,
+// which can't hold all-day dates or dates without offset.
+func do(ctx context.Context, cli *notion.Client, method, path string, body interface{}) ([]byte, error) {
+	var r io.Reader = http.NoBody
+
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encoding body: %w", err)
+		}
+
+		r = bytes.NewReader(b)
+	}
+
+	u, err := cli.BaseURL.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), r)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Header.Add(client.ContentType, client.MIMEApplicationJSON)
+	}
+
+	for _, edit := range cli.RequestEditors {
+		if err := edit(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := cli.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, responseError(resp.StatusCode, b)
+	}
+
+	return b, nil
+}
+
+// pagePath returns the path of the page with the given ID.
+func pagePath(id notion.Id) string {
+	return "./v1/pages/" + url.PathEscape(string(id))
+}
+
+// responseError returns the error notion responded with.
+// Any status that is not successful comes with a notion.Error, as long as the body holds one.
+func responseError(status int, body []byte) error {
+	notionErr := &notion.Error{}
+	if err := json.Unmarshal(body, notionErr); err == nil && (notionErr.Object == "error" || notionErr.Code != "") {
+		if notionErr.Status == 0 {
+			notionErr.Status = status
+		}
+
+		return notionErr
+	}
+
+	return fmt.Errorf("unknown error response (%d): %v", status, string(body))
+}
@@ -0,0 +1,124 @@
+package database
+
+import (
+	"encoding/json"
+
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// RollupType is the type of the result of a rollup.
+type RollupType string
+
+// The types of the results of rollups.
+const (
+	RollupNumber RollupType = "number"
+	RollupDate   RollupType = "date"
+	RollupArray  RollupType = "array"
+)
+
+// Rollup is the result of a rollup, which holds a value of its type.
+type Rollup struct {
+	Type RollupType
+	// Function is the function of the rollup, e.g. sum or show_original.
+	Function string
+	Number   float64
+	Date     DateValue
+	// Array holds the values of the related pages if the rollup shows them.
+	Array []Value
+}
+
+// rawRollup is a rollup as Notion sends it.
+type rawRollup struct {
+	Type     RollupType   `json:"type"`
+	Function string       `json:"function,omitempty"`
+	Number   *json.Number `json:"number,omitempty"`
+	Date     *rawDate     `json:"date,omitempty"`
+	Array    []Value      `json:"array,omitempty"`
+}
+
+// NewRollup decodes the result of a rollup.
+// The result is empty if the rollup can't be decoded.
+func NewRollup(raw json.RawMessage) Rollup {
+	r := rawRollup{}
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return Rollup{}
+	}
+
+	res := Rollup{Type: r.Type, Function: r.Function, Array: r.Array}
+
+	if r.Number != nil {
+		res.Number, _ = r.Number.Float64()
+	}
+
+	if r.Date != nil {
+		if d, err := r.Date.decode(); err == nil {
+			res.Date = NewDateValue(d)
+		}
+	}
+
+	return res
+}
+
+// MarshalJSON encodes the rollup as Notion sends it.
+func (r Rollup) MarshalJSON() ([]byte, error) {
+	raw := rawRollup{Type: r.Type, Function: r.Function}
+
+	switch r.Type {
+	case RollupNumber:
+		n := Float64Number(r.Number)
+		raw.Number = &n
+	case RollupDate:
+		if d := r.Date.Date(); d != nil {
+			b, err := encodeDate(*d)
+			if err != nil {
+				return nil, err
+			}
+
+			raw.Date = &rawDate{}
+			if err := json.Unmarshal(b, raw.Date); err != nil {
+				return nil, err
+			}
+		}
+	case RollupArray:
+		raw.Array = r.Array
+	}
+
+	return json.Marshal(raw)
+}
+
+// Numbers returns the numbers of the values the rollup shows, leaving out empty ones.
+func (r Rollup) Numbers() []float64 {
+	var nums []float64
+
+	for _, v := range r.Array {
+		if v.Number != nil {
+			nums = append(nums, Float64(v.PropertyValue, v.Exact))
+		}
+	}
+
+	return nums
+}
+
+// Dates returns the dates of the values the rollup shows, leaving out empty ones.
+func (r Rollup) Dates() []DateValue {
+	var dates []DateValue
+
+	for _, v := range r.Array {
+		if v.Date != nil {
+			dates = append(dates, NewDateValue(*v.Date))
+		}
+	}
+
+	return dates
+}
+
+// Values returns the values the rollup shows.
+func (r Rollup) Values() []notion.PropertyValue {
+	var values []notion.PropertyValue
+
+	for _, v := range r.Array {
+		values = append(values, v.PropertyValue)
+	}
+
+	return values
+}
@@ -0,0 +1,68 @@
+package database_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollup(t *testing.T) {
+	t.Parallel()
+
+	const raw = `{"object":"page","id":"page","properties":{
+		"Name":{"id":"title","type":"title","title":[]},
+		"Total":{"id":"a","type":"rollup","rollup":{"type":"number","number":16777217,"function":"sum"}},
+		"Latest":{"id":"b","type":"rollup","rollup":{"type":"date","date":{"start":"2022-05-01"},"function":"latest_date"}},
+		"Views":{"id":"c","type":"rollup","rollup":{"type":"array","array":[
+			{"type":"number","number":16777217},
+			{"type":"number","number":null}
+		],"function":"show_original"}},
+		"Due":{"id":"d","type":"rollup","rollup":{"type":"array","array":[
+			{"type":"date","date":{"start":"2022-05-01"}},
+			{"type":"date","date":null}
+		],"function":"show_unique"}}
+	}}`
+
+	p := database.Page{}
+	require.NoError(t, json.Unmarshal([]byte(raw), &p))
+	assert.Len(t, p.Raw, 4)
+
+	total := database.NewRollup(p.Raw["Total"])
+	assert.Equal(t, database.RollupNumber, total.Type)
+	assert.Equal(t, "sum", total.Function)
+	assert.Equal(t, 16777217.0, total.Number)
+
+	may := database.DateValue{Start: time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC), AllDay: true}
+	assert.Equal(t, may, database.NewRollup(p.Raw["Latest"]).Date)
+
+	views := database.NewRollup(p.Raw["Views"])
+	assert.Equal(t, database.RollupArray, views.Type)
+	assert.Equal(t, []float64{16777217}, views.Numbers())
+	assert.Len(t, views.Values(), 2)
+	assert.Equal(t, notion.PropertyTypeNumber, views.Values()[0].Type)
+	assert.Empty(t, views.Dates())
+
+	assert.Equal(t, []database.DateValue{may}, database.NewRollup(p.Raw["Due"]).Dates())
+	assert.Equal(t, database.Rollup{}, database.NewRollup(nil))
+
+	b, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"rollup":{"type":"number","number":16777217,"function":"sum"}`)
+
+	got := database.Page{}
+	require.NoError(t, json.Unmarshal(b, &got))
+
+	for _, key := range []string{"Total", "Latest", "Views", "Due"} {
+		r := database.NewRollup(p.Raw[key])
+		assert.Equal(t, r, database.NewRollup(got.Raw[key]), key)
+
+		b, err := json.Marshal(r)
+		require.NoError(t, err)
+		assert.Equal(t, r, database.NewRollup(b), key)
+	}
+}
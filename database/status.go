@@ -0,0 +1,70 @@
+package database
+
+import (
+	"encoding/json"
+
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// PropertyTypeStatus is the type of status properties, which notion.PropertyType lacks.
+//
+// notion.PropertyValue can't hold status values either, so they are part of the raw values of a page.
+const PropertyTypeStatus notion.PropertyType = "status"
+
+// StatusConfig holds the options of a status property and the groups they belong to,
+// which notion.PropertyMeta can't hold.
+type StatusConfig struct {
+	Options []notion.PropertyOption `json:"options"`
+	Groups  []StatusGroup           `json:"groups"`
+}
+
+// StatusGroup is a group of options of a status property, e.g. To-do, In progress or Complete.
+type StatusGroup struct {
+	Id        string       `json:"id"`
+	Name      string       `json:"name"`
+	Color     notion.Color `json:"color"`
+	OptionIds []string     `json:"option_ids"`
+}
+
+// Group returns the name of the group of the option with the given name
+// or nothing if it belongs to no group.
+func (c StatusConfig) Group(option string) string {
+	for _, opt := range c.Options {
+		if opt.Name != option {
+			continue
+		}
+
+		for _, g := range c.Groups {
+			if contains(g.OptionIds, opt.Id) {
+				return g.Name
+			}
+		}
+	}
+
+	return ""
+}
+
+// NewStatus decodes the option of a status property.
+// The option is empty if it can't be decoded.
+func NewStatus(raw json.RawMessage) notion.SelectValue {
+	v := notion.SelectValue{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return notion.SelectValue{}
+	}
+
+	return v
+}
+
+// Status returns the raw value of a status property with the option of the given name,
+// which is empty if the name is.
+func Status(name string) json.RawMessage {
+	if name == "" {
+		return json.RawMessage("null")
+	}
+
+	b, _ := json.Marshal(struct {
+		Name string `json:"name"`
+	}{name})
+
+	return b
+}
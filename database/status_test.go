@@ -0,0 +1,68 @@
+package database_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	c := database.StatusConfig{
+		Options: []notion.PropertyOption{{Id: "a", Name: "Not started"}, {Id: "b", Name: "Done"}, {Id: "c", Name: "Lost"}},
+		Groups: []database.StatusGroup{
+			{Name: "To-do", OptionIds: []string{"a"}},
+			{Name: "Complete", OptionIds: []string{"b"}},
+		},
+	}
+
+	assert.Equal(t, "To-do", c.Group("Not started"))
+	assert.Equal(t, "Complete", c.Group("Done"))
+	assert.Empty(t, c.Group("Lost"))
+	assert.Empty(t, c.Group("Unknown"))
+
+	p := database.Page{}
+	require.NoError(t, json.Unmarshal([]byte(`{"object":"page","id":"page","properties":{
+		"Stage":{"id":"s","type":"status","status":{"id":"b","name":"Done","color":"green"}},
+		"Empty":{"id":"e","type":"status","status":null}
+	}}`), &p))
+
+	assert.Equal(t, notion.SelectValue{Id: "b", Name: "Done", Color: "green"}, database.NewStatus(p.Raw["Stage"]))
+	assert.Equal(t, notion.SelectValue{}, database.NewStatus(p.Raw["Empty"]))
+
+	assert.JSONEq(t, `{"name":"Done"}`, string(database.Status("Done")))
+	assert.JSONEq(t, `null`, string(database.Status("")))
+
+	b, err := json.Marshal(database.Page{
+		Page: notion.Page{Properties: notion.PropertyValueMap{"Stage": {Type: database.PropertyTypeStatus}}},
+		Raw:  database.RawValues{"Stage": database.Status("Done")},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"Stage":{"status":{"name":"Done"},"type":"status"}`)
+}
+
+func TestDiffRaw(t *testing.T) {
+	t.Parallel()
+
+	props := notion.PropertyValueMap{
+		"Stage": {Type: database.PropertyTypeStatus},
+		"Owner": {Type: database.PropertyTypeStatus},
+	}
+
+	from := database.RawValues{"Stage": database.Status("Done"), "Owner": json.RawMessage(`null`)}
+
+	assert.Empty(t, database.DiffRaw(props, props, nil, nil, from, database.RawValues{
+		"Stage": json.RawMessage(`{ "name": "Done" }`),
+	}))
+
+	assert.Equal(t, notion.PropertyValueMap{"Stage": props["Stage"]},
+		database.DiffRaw(props, props, nil, nil, from, database.RawValues{
+			"Stage": database.Status("In progress"),
+			"Owner": database.Status(""),
+		}))
+}
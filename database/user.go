@@ -0,0 +1,174 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// NewUsers decodes the users of a people property.
+// There are none if they can't be decoded.
+func NewUsers(raw json.RawMessage) []notion.User {
+	users := []notion.User{}
+	if err := json.Unmarshal(raw, &users); err != nil {
+		return nil
+	}
+
+	return users
+}
+
+// NewUser decodes the user of a created by or last edited by property.
+// The user is empty if it can't be decoded.
+func NewUser(raw json.RawMessage) notion.User {
+	u := notion.User{}
+	if err := json.Unmarshal(raw, &u); err != nil {
+		return notion.User{}
+	}
+
+	return u
+}
+
+// Directory holds the users of a workspace, so that users of property values,
+// which often only have an ID, can be resolved to their names and emails.
+//
+// The users are loaded once, when they are first needed.
+type Directory struct {
+	cli *notion.Client
+
+	mu    sync.Mutex
+	users map[string]notion.User
+}
+
+// NewDirectory returns a directory of the users the client can see.
+func NewDirectory(cli *notion.Client) *Directory {
+	return &Directory{cli: cli}
+}
+
+// Load loads the users, unless they were loaded before.
+func (d *Directory) Load(ctx context.Context) error {
+	_, err := d.load(ctx)
+	return err
+}
+
+// User returns the user with the given ID and whether it is part of the workspace.
+func (d *Directory) User(ctx context.Context, id notion.UUID) (notion.User, bool, error) {
+	users, err := d.load(ctx)
+	if err != nil {
+		return notion.User{}, false, err
+	}
+
+	u, ok := users[NormalizeID(id)]
+
+	return u, ok, nil
+}
+
+// Name returns the name of the user.
+// Users that are not part of the workspace keep the name they have.
+func (d *Directory) Name(ctx context.Context, u notion.User) (string, error) {
+	u, err := d.resolve(ctx, u)
+	return u.Name, err
+}
+
+// Email returns the email of the user or nothing if it is not known, e.g. because the user is a bot.
+func (d *Directory) Email(ctx context.Context, u notion.User) (string, error) {
+	u, err := d.resolve(ctx, u)
+	if err != nil || u.Person == nil {
+		return "", err
+	}
+
+	return string(u.Person.Email), nil
+}
+
+// Names returns the names of the users.
+func (d *Directory) Names(ctx context.Context, users []notion.User) ([]string, error) {
+	names := make([]string, len(users))
+
+	for i, u := range users {
+		name, err := d.Name(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+
+		names[i] = name
+	}
+
+	return names, nil
+}
+
+// Emails returns the emails of the users, leaving out those that are not known.
+func (d *Directory) Emails(ctx context.Context, users []notion.User) ([]string, error) {
+	emails := []string{}
+
+	for _, u := range users {
+		email, err := d.Email(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+
+		if email != "" {
+			emails = append(emails, email)
+		}
+	}
+
+	return emails, nil
+}
+
+// resolve returns the user of the directory with the ID of the given one, if there is one.
+func (d *Directory) resolve(ctx context.Context, u notion.User) (notion.User, error) {
+	full, ok, err := d.User(ctx, u.Id)
+	if err != nil || !ok {
+		return u, err
+	}
+
+	return full, nil
+}
+
+// load returns the users by normalized ID, loading them if they were not loaded before.
+// If loading fails, it is tried again the next time.
+func (d *Directory) load(ctx context.Context) (map[string]notion.User, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.users != nil {
+		return d.users, nil
+	}
+
+	users := map[string]notion.User{}
+	cursor := ""
+
+	for {
+		path := "./v1/users?page_size=100"
+		if cursor != "" {
+			path += "&start_cursor=" + url.QueryEscape(cursor)
+		}
+
+		b, err := do(ctx, d.cli, http.MethodGet, path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("listing users: %w", err)
+		}
+
+		list := notion.UsersList{}
+		if err := json.Unmarshal(b, &list); err != nil {
+			return nil, fmt.Errorf("decoding users: %w", err)
+		}
+
+		for _, u := range list.Results {
+			users[NormalizeID(u.Id)] = u
+		}
+
+		if !list.HasMore || list.NextCursor == "" {
+			break
+		}
+
+		cursor = list.NextCursor
+	}
+
+	d.users = users
+
+	return users, nil
+}
@@ -0,0 +1,125 @@
+package database_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/faetools/client"
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUsers(t *testing.T) {
+	t.Parallel()
+
+	const raw = `{"object":"page","id":"page","properties":{
+		"Name":{"id":"title","type":"title","title":[]},
+		"Assignees":{"id":"a","type":"people","people":[{"object":"user","id":"alice"},{"object":"user","id":"bob"}]},
+		"Created By":{"id":"b","type":"created_by","created_by":{"object":"user","id":"alice"}}
+	}}`
+
+	p := database.Page{}
+	require.NoError(t, json.Unmarshal([]byte(raw), &p))
+
+	users := database.NewUsers(p.Raw["Assignees"])
+	require.Len(t, users, 2)
+	assert.Equal(t, notion.UUID("bob"), users[1].Id)
+
+	assert.Equal(t, notion.UUID("alice"), database.NewUser(p.Raw["Created By"]).Id)
+
+	assert.Nil(t, database.NewUsers(nil))
+	assert.Equal(t, notion.User{}, database.NewUser(nil))
+}
+
+func TestDirectory(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		list := notion.UsersList{Object: "list"}
+
+		if r.URL.Query().Get("start_cursor") == "" {
+			list.Results = notion.Users{{
+				Id:     "alice",
+				Name:   "Alice",
+				Type:   notion.UserTypePerson,
+				Person: &notion.Person{Email: "alice@example.com"},
+			}}
+			list.HasMore, list.NextCursor = true, "bot"
+		} else {
+			list.Results = notion.Users{{Id: "bot", Name: "Bot", Type: notion.UserTypeBot}}
+		}
+
+		assert.NoError(t, json.NewEncoder(w).Encode(list))
+	}))
+	defer srv.Close()
+
+	cli, err := notion.NewDefaultClient("secret", client.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	dir := database.NewDirectory(cli)
+
+	users := []notion.User{{Id: "alice"}, {Id: "bot"}, {Id: "guest", Name: "Guest"}}
+
+	names, err := dir.Names(ctx, users)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Alice", "Bot", "Guest"}, names)
+
+	emails, err := dir.Emails(ctx, users)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice@example.com"}, emails)
+
+	u, ok, err := dir.User(ctx, "ALICE")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "Alice", u.Name)
+
+	_, ok, err = dir.User(ctx, "guest")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// the users are only loaded once
+	assert.EqualValues(t, 2, atomic.LoadInt32(&requests))
+}
+
+func TestDirectory_Error(t *testing.T) {
+	t.Parallel()
+
+	fail := int32(1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if atomic.CompareAndSwapInt32(&fail, 1, 0) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		assert.NoError(t, json.NewEncoder(w).Encode(notion.UsersList{
+			Object:  "list",
+			Results: notion.Users{{Id: "alice", Name: "Alice"}},
+		}))
+	}))
+	defer srv.Close()
+
+	cli, err := notion.NewDefaultClient("secret", client.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	dir := database.NewDirectory(cli)
+
+	assert.ErrorContains(t, dir.Load(ctx), "listing users")
+
+	// failures are not kept
+	name, err := dir.Name(ctx, notion.User{Id: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", name)
+}
@@ -0,0 +1,171 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+var jsonNumber = regexp.MustCompile(`^-?(0|[1-9]\d*)(\.\d+)?([eE][+-]?\d+)?$`)
+
+// ValidationError describes why the value of a property is invalid.
+type ValidationError struct {
+	Property string
+	Reason   string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s %s", e.Property, e.Reason)
+}
+
+// ValidationErrors are all reasons why property values are invalid.
+type ValidationErrors []*ValidationError
+
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	return "invalid property values: " + strings.Join(msgs, "; ")
+}
+
+// Validator collects the reasons why property values are invalid.
+// It is used by the generated Validate methods.
+type Validator struct {
+	errs ValidationErrors
+}
+
+// Err returns the ValidationErrors that were found or nil if the values are valid.
+func (v *Validator) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+
+	return v.errs
+}
+
+func (v *Validator) addf(prop, format string, args ...interface{}) {
+	v.errs = append(v.errs, &ValidationError{Property: prop, Reason: fmt.Sprintf(format, args...)})
+}
+
+// Option checks that the name is one of the options, unless it is empty.
+func (v *Validator) Option(prop, name string, options ...string) {
+	if name != "" && !contains(options, name) {
+		v.addf(prop, "has no option %q", name)
+	}
+}
+
+// Options checks that all options are declared.
+func (v *Validator) Options(prop string, opts notion.PropertyOptions, options ...string) {
+	for _, opt := range opts {
+		v.Option(prop, opt.Name, options...)
+	}
+}
+
+// Names checks that all names are options, like Options.
+func (v *Validator) Names(prop string, names []string, options ...string) {
+	for _, name := range names {
+		v.Option(prop, name, options...)
+	}
+}
+
+// maxSafeInt is the largest integer that Notion, which keeps numbers as float64, holds exactly,
+// along with all integers closer to zero.
+const maxSafeInt = 1<<53 - 1
+
+// Int checks that Notion can hold the number without losing precision.
+func (v *Validator) Int(prop string, n int) {
+	if n > maxSafeInt || n < -maxSafeInt {
+		v.addf(prop, "can't hold %d as an integer", n)
+	}
+}
+
+// Integral checks that the JSON number a whole number was read from has no fraction,
+// unless the whole number was changed since.
+func (v *Validator) Integral(prop string, n json.Number, i int64) {
+	r, ok := new(big.Rat).SetString(string(n))
+	if !ok || r.IsInt() || Int64(notion.PropertyValue{}, n) != i {
+		return
+	}
+
+	v.addf(prop, "holds %s, which is no integer", n)
+}
+
+// DateRange checks that a date range does not end before it starts and that its time zone is known.
+func (v *Validator) DateRange(prop string, d DateValue) {
+	if d.IsRange() && d.End.Before(d.Start) {
+		v.addf(prop, "ends before it starts")
+	}
+
+	if d.TimeZone != "" {
+		if _, err := time.LoadLocation(d.TimeZone); err != nil {
+			v.addf(prop, "has unknown time zone %q", d.TimeZone)
+		}
+	}
+}
+
+// Currency checks that the money has the given currency, unless its currency is not set.
+func (v *Validator) Currency(prop string, m Money, c Currency) {
+	if m.Currency != "" && m.Currency != c {
+		v.addf(prop, "must be in %s, not %s", c, m.Currency)
+	}
+}
+
+// Decimal checks that the decimal is a number.
+func (v *Validator) Decimal(prop string, d Decimal) {
+	if d != "" && !jsonNumber.MatchString(string(d)) {
+		v.addf(prop, "is not a number: %q", d)
+	}
+}
+
+// Required checks that the property is set.
+func (v *Validator) Required(prop string, set bool) {
+	if !set {
+		v.addf(prop, "is required")
+	}
+}
+
+// Min checks that the number is not less than min.
+func (v *Validator) Min(prop string, n, min float64) {
+	if n < min {
+		v.addf(prop, "must be at least %v", min)
+	}
+}
+
+// Max checks that the number is not greater than max.
+func (v *Validator) Max(prop string, n, max float64) {
+	if n > max {
+		v.addf(prop, "must be at most %v", max)
+	}
+}
+
+// Pattern checks that the text matches the pattern, unless it is empty.
+func (v *Validator) Pattern(prop, text string, pattern *regexp.Regexp) {
+	if text != "" && !pattern.MatchString(text) {
+		v.addf(prop, "must match %s", pattern)
+	}
+}
+
+// MaxLength checks that the text has at most n characters.
+func (v *Validator) MaxLength(prop, text string, n int) {
+	if utf8.RuneCountInString(text) > n {
+		v.addf(prop, "must have at most %d characters", n)
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
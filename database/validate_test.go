@@ -0,0 +1,60 @@
+package database_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidator(t *testing.T) {
+	t.Parallel()
+
+	val := &database.Validator{}
+	val.Option("Size", "", "S", "M")
+	val.Option("Size", "M", "S", "M")
+	val.Int("Count", 1<<24+1)
+	val.Int("Count", -(1<<53 - 1))
+	val.Integral("Count", "", 0)
+	val.Integral("Count", "2", 2)
+	val.Integral("Count", "1e3", 1000)
+	val.Integral("Count", "1.5", 2) // changed since
+	val.Required("Done", true)
+	val.Min("Count", 1, 1)
+	val.Max("Count", 2, 2)
+	val.Pattern("Code", "", regexp.MustCompile(`^\d+$`))
+	val.MaxLength("Name", "äöü", 3)
+	val.DateRange("Due", database.DateValue{Start: time.Now(), TimeZone: "Europe/Berlin"})
+	assert.NoError(t, val.Err())
+
+	start := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(-time.Hour)
+
+	val.Option("Size", "XL", "S", "M")
+	val.Options("Tags", notion.PropertyOptions{{Name: "a"}, {Name: "b"}}, "a")
+	val.Int("Count", 1<<53+1)
+	val.Integral("Count", "1.5", 1)
+	val.Required("Done", false)
+	val.Min("Count", 0, 1)
+	val.Max("Count", 3, 2)
+	val.Pattern("Code", "x", regexp.MustCompile(`^\d+$`))
+	val.MaxLength("Name", "abcd", 3)
+	val.DateRange("Due", database.DateValue{Start: start, End: end})
+	val.DateRange("Start", database.DateValue{Start: start, TimeZone: "Mars/Olympus"})
+
+	err := val.Err()
+
+	var errs database.ValidationErrors
+	if assert.True(t, errors.As(err, &errs)) {
+		assert.Len(t, errs, 11)
+		assert.Equal(t, &database.ValidationError{Property: "Size", Reason: `has no option "XL"`}, errs[0])
+	}
+
+	assert.Contains(t, err.Error(), "Count holds 1.5, which is no integer")
+	assert.Contains(t, err.Error(), "Due ends before it starts")
+	assert.Contains(t, err.Error(), `Start has unknown time zone "Mars/Olympus"`)
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"time"
+
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// Number returns a pointer to the number as it is used in a property value.
+func Number[T ~int | ~int64 | ~float32 | ~float64](n T) *float32 {
+	f := float32(n)
+	return &f
+}
+
+// Select returns a pointer to the select value or nil if nothing is selected.
+func Select(v notion.SelectValue) *notion.SelectValue {
+	if v.Id == "" && v.Name == "" {
+		return nil
+	}
+
+	return &v
+}
+
+// Date returns a pointer to the date or nil if it is not set.
+func Date(d notion.Date) *notion.Date {
+	if d.Start.IsZero() {
+		return nil
+	}
+
+	return &d
+}
+
+// References returns the IDs as references.
+func References[T ~string](ids []T) *notion.References {
+	refs := make(notion.References, len(ids))
+
+	for i, id := range ids {
+		refs[i] = notion.Reference{Id: notion.UUID(id)}
+	}
+
+	return &refs
+}
+
+// Text returns a pointer to rich texts with the given content, which are empty if it is.
+func Text(s string) *notion.RichTexts {
+	if s == "" {
+		return &notion.RichTexts{}
+	}
+
+	ts := notion.NewRichTexts(s)
+
+	return &ts
+}
+
+// SelectName returns a pointer to the select value with the given name or nil if it is empty.
+func SelectName(name string) *notion.SelectValue {
+	return Select(notion.SelectValue{Name: name})
+}
+
+// Options returns a pointer to the options with the given names.
+func Options(names []string) *notion.PropertyOptions {
+	opts := make(notion.PropertyOptions, len(names))
+
+	for i, name := range names {
+		opts[i] = notion.PropertyOption{Name: name}
+	}
+
+	return &opts
+}
+
+// Time returns a pointer to a date starting at the given time or nil if it is zero.
+func Time(t time.Time) *notion.Date {
+	return Date(notion.Date{Start: t})
+}
@@ -0,0 +1,28 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlainValues(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, &notion.RichTexts{}, database.Text(""))
+	assert.Equal(t, "hello", database.Text("hello").Content())
+
+	assert.Nil(t, database.SelectName(""))
+	assert.Equal(t, &notion.SelectValue{Name: "A"}, database.SelectName("A"))
+
+	assert.Equal(t, &notion.PropertyOptions{}, database.Options(nil))
+	assert.Equal(t, []string{"A", "B"}, database.Options([]string{"A", "B"}).GetNames())
+
+	now := time.Now()
+
+	assert.Nil(t, database.Time(time.Time{}))
+	assert.Equal(t, &notion.Date{Start: now}, database.Time(now))
+}
@@ -1,29 +1,210 @@
+// Code generated by go-notion-codegen v0.0.2 from schema 97e364c87aa66c1cd393cc8b72b492a3293f9349b8ac81f22f3032bde895bfef with generator b20b9e0aa911b4941c2287355a392295620c76b002080214779e2d185414b799; DO NOT EDIT.
+
 package bar
 
-import "github.com/faetools/go-notion/pkg/notion"
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion-codegen/example/databases/foo"
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+var namePattern = regexp.MustCompile("^[A-Z][a-z]+( [a-z]+)*$")
+
+// The names of the properties in Notion.
+const (
+	PropAllViews       = "All Views"
+	PropCategory       = "Category"
+	PropDescription    = "Description"
+	PropDraft          = "Draft"
+	PropExpires        = "Expires"
+	PropLabels         = "Labels"
+	PropName           = "Name"
+	PropNumberOfPeople = "Number Of People"
+	PropRelatedTo      = "Related To"
+	PropResources      = "Resources"
+	PropTotalBudget    = "Total Budget"
+)
+
+// The IDs of the properties in Notion, which don't change when the properties are renamed.
+const (
+	PropNameID = "title"
+)
+
+// schema holds the properties of the database the package was generated from.
+const schema = `{
+	"All Views": {
+		"id": "",
+		"name": "",
+		"type": "rollup"
+	},
+	"Category": {
+		"id": "",
+		"name": "",
+		"select": {
+			"options": []
+		},
+		"type": "select"
+	},
+	"Description": {
+		"id": "",
+		"name": "",
+		"rich_text": {},
+		"type": "rich_text"
+	},
+	"Draft": {
+		"checkbox": {},
+		"id": "",
+		"name": "",
+		"type": "checkbox"
+	},
+	"Expires": {
+		"date": {},
+		"id": "",
+		"name": "",
+		"type": "date"
+	},
+	"Labels": {
+		"id": "",
+		"multi_select": {
+			"options": []
+		},
+		"name": "",
+		"type": "multi_select"
+	},
+	"Name": {
+		"id": "title",
+		"name": "Name",
+		"title": {},
+		"type": "title"
+	},
+	"Number Of People": {
+		"id": "",
+		"name": "",
+		"number": {
+			"format": "number"
+		},
+		"type": "number"
+	},
+	"Related To": {
+		"id": "",
+		"name": "",
+		"relation": {
+			"database_id": "5f3a9c8e-1d2b-4c6a-9e7f-0a1b2c3d4e5f",
+			"synced_property_name": ""
+		},
+		"type": "relation"
+	},
+	"Resources": {
+		"files": {},
+		"id": "",
+		"name": "",
+		"type": "files"
+	},
+	"Total Budget": {
+		"id": "",
+		"name": "",
+		"type": "rollup"
+	}
+}`
+
+// Schema returns the properties of the database the package was generated from.
+func Schema() notion.PropertyMetaMap {
+	props := notion.PropertyMetaMap{}
+	if err := json.Unmarshal([]byte(schema), &props); err != nil {
+		panic(err)
+	}
+
+	return props
+}
 
 type PropertyValues struct {
+	AllViews       []float64
 	Category       notion.SelectValue
 	Description    notion.RichTexts
 	Draft          bool
-	Expires        notion.Date
+	Expires        database.DateValue
 	Labels         notion.PropertyOptions
 	Name           notion.RichTexts
 	NumberOfPeople int
-	RelatedTo      notion.References
+	RelatedTo      []foo.ID
 	Resources      notion.Files
+	TotalBudget    float64
 }
 
 func GetPropertyValues(props notion.PropertyValueMap) PropertyValues {
+	return GetExactPropertyValues(props, nil, nil)
+}
+
+// GetExactPropertyValues returns the property values, using the exact numbers
+// and the raw values notion.PropertyValue can't hold, where they are given.
+func GetExactPropertyValues(props notion.PropertyValueMap, nums database.Numbers, raw database.RawValues) PropertyValues {
 	return PropertyValues{
+		AllViews:       database.NewRollup(raw["All Views"]).Numbers(),
 		Category:       props["Category"].GetSelect(),
 		Description:    props["Description"].GetRichText(),
 		Draft:          props["Draft"].GetCheckbox(),
-		Expires:        props["Expires"].GetDate(),
+		Expires:        database.NewDateValue(props["Expires"].GetDate()),
 		Labels:         props["Labels"].GetMultiSelect(),
 		Name:           props["Name"].GetTitle(),
-		NumberOfPeople: int(props["Number Of People"].GetNumber()),
-		RelatedTo:      props["Related To"].GetRelation(),
+		NumberOfPeople: int(database.Int64(props["Number Of People"], nums["Number Of People"])),
+		RelatedTo:      database.IDs[foo.ID](props["Related To"].GetRelation()),
 		Resources:      props["Resources"].GetFiles(),
+		TotalBudget:    database.NewRollup(raw["Total Budget"]).Number,
+	}
+}
+
+// ToPropertyValueMap returns the property values as a property value map.
+func (v PropertyValues) ToPropertyValueMap() notion.PropertyValueMap {
+	return notion.PropertyValueMap{
+		"Category":         {Type: notion.PropertyTypeSelect, Select: database.Select(v.Category)},
+		"Description":      {Type: notion.PropertyTypeRichText, RichText: &v.Description},
+		"Draft":            {Type: notion.PropertyTypeCheckbox, Checkbox: &v.Draft},
+		"Expires":          {Type: notion.PropertyTypeDate, Date: v.Expires.Date()},
+		"Labels":           {Type: notion.PropertyTypeMultiSelect, MultiSelect: &v.Labels},
+		"Name":             {Id: "title", Type: notion.PropertyTypeTitle, Title: &v.Name},
+		"Number Of People": {Type: notion.PropertyTypeNumber, Number: database.Number(v.NumberOfPeople)},
+		"Related To":       {Type: notion.PropertyTypeRelation, Relation: database.References(v.RelatedTo)},
+		"Resources":        {Type: notion.PropertyTypeFiles, Files: &v.Resources},
 	}
 }
+
+// ToNumbers returns the exact numbers of the property values.
+func (v PropertyValues) ToNumbers() database.Numbers {
+	return database.Numbers{
+		"Number Of People": database.Int64Number(int64(v.NumberOfPeople)),
+	}
+}
+
+// ToRawValues returns the raw values of the property values notion.PropertyValue can't hold.
+func (v PropertyValues) ToRawValues() database.RawValues {
+	return database.RawValues{}
+}
+
+// Diff returns the property values of to that differ from those of from,
+// as a property value map that can be used to update a page.
+func Diff(from, to PropertyValues) notion.PropertyValueMap {
+	return database.DiffRaw(from.ToPropertyValueMap(), to.ToPropertyValueMap(),
+		from.ToNumbers(), to.ToNumbers(), from.ToRawValues(), to.ToRawValues())
+}
+
+// Validate checks that the property values fit the schema of the database and its constraints.
+func (v PropertyValues) Validate() error {
+	val := &database.Validator{}
+	v.validate(val)
+
+	return val.Err()
+}
+
+// validate adds the reasons why the property values are invalid to the validator.
+func (v PropertyValues) validate(val *database.Validator) {
+	val.MaxLength("Description", v.Description.Content(), 20)
+	val.DateRange("Expires", v.Expires)
+	val.Required("Name", v.Name.Content() != "")
+	val.Pattern("Name", v.Name.Content(), namePattern)
+	val.MaxLength("Name", v.Name.Content(), 30)
+	val.Int("Number Of People", v.NumberOfPeople)
+	val.Min("Number Of People", float64(v.NumberOfPeople), 0)
+}
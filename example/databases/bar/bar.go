@@ -3,6 +3,7 @@ package bar
 import (
 	"context"
 
+	"github.com/faetools/go-notion-codegen/example/databases/foo"
 	"github.com/faetools/go-notion/pkg/notion"
 )
 
@@ -36,7 +37,7 @@ var Properties = notion.PropertyMetaMap{
 		Type:        notion.PropertyTypeMultiSelect,
 		MultiSelect: noOptions,
 	},
-	"Number of People": notion.PropertyMeta{
+	"Number Of People": notion.PropertyMeta{
 		Type: notion.PropertyTypeNumber,
 		Number: &notion.NumberConfig{
 			Format: notion.NumberConfigFormatNumber,
@@ -51,7 +52,7 @@ var Properties = notion.PropertyMetaMap{
 	"Related To": notion.PropertyMeta{
 		Type: notion.PropertyTypeRelation,
 		Relation: &notion.RelationConfiguration{
-			DatabaseId: "some id",
+			DatabaseId: foo.DatabaseID,
 		},
 	},
 	"Description": notion.PropertyMeta{
@@ -0,0 +1,255 @@
+package bar_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion-codegen/example/databases/bar"
+	"github.com/faetools/go-notion-codegen/example/databases/foo"
+	"github.com/faetools/go-notion-codegen/notiontest"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func all(t *testing.T, it *database.Iterator[bar.Entry]) []string {
+	t.Helper()
+
+	names := []string{}
+
+	for {
+		e, err := it.Next(context.Background())
+		if errors.Is(err, database.ErrDone) {
+			return names
+		}
+
+		require.NoError(t, err)
+
+		names = append(names, e.Name.Content())
+	}
+}
+
+func TestFake(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	var store bar.Store = bar.NewFake()
+
+	for i, name := range []string{"Alice", "Bob", "Carol"} {
+		_, err := store.Create(ctx, bar.PropertyValues{
+			Name:           notion.NewRichTexts(name),
+			Draft:          i%2 == 0,
+			NumberOfPeople: 3 - i,
+		})
+		require.NoError(t, err)
+	}
+
+	draft := bar.PropDraft
+	drafts := &notion.Filter{Property: &draft, Checkbox: &notion.CheckboxFilter{Equals: true}}
+	byPeople := &notion.Sorts{{Property: bar.PropNumberOfPeople, Direction: notion.SortDirectionAscending}}
+
+	assert.Equal(t, []string{"Carol", "Alice"}, all(t, store.Query(drafts, byPeople)))
+
+	bob := store.Query(nil, nil)
+	e, err := bob.Next(ctx)
+	require.NoError(t, err)
+
+	e, err = bob.Next(ctx)
+	require.NoError(t, err)
+
+	// an entry without changes is returned as it is, even with read-only values changed
+	e.TotalBudget = 42
+
+	unchanged, err := store.Update(ctx, e)
+	require.NoError(t, err)
+	assert.Equal(t, e, unchanged)
+
+	e.Draft = true
+
+	_, err = store.Update(ctx, e)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Alice", "Bob", "Carol"}, all(t, store.Query(drafts, nil)))
+
+	require.NoError(t, store.Archive(ctx, e.ID))
+	assert.Equal(t, []string{"Alice", "Carol"}, all(t, store.Query(drafts, nil)))
+
+	got, err := store.Get(ctx, e.ID)
+	require.NoError(t, err)
+	assert.True(t, got.Page.Archived)
+
+	// IDs are found with or without dashes, in any case
+	got, err = store.Get(ctx, bar.ID(strings.ToUpper(strings.ReplaceAll(string(e.ID), "-", ""))))
+	require.NoError(t, err)
+	assert.Equal(t, e.ID, got.ID)
+
+	_, err = store.Create(ctx, bar.PropertyValues{NumberOfPeople: -1})
+
+	var invalid database.ValidationErrors
+	require.ErrorAs(t, err, &invalid)
+	assert.Len(t, invalid, 2)
+
+	e.Name = nil
+
+	_, err = store.Update(ctx, e)
+	assert.ErrorAs(t, err, &invalid)
+
+	_, err = store.Get(ctx, "unknown")
+
+	var notionErr *notion.Error
+	assert.ErrorAs(t, err, &notionErr)
+
+	// whole numbers beyond the precision of a float32 are valid
+	e, err = store.Create(ctx, bar.PropertyValues{Name: notion.NewRichTexts("Dave"), NumberOfPeople: 1<<24 + 1})
+	require.NoError(t, err)
+	assert.Equal(t, 1<<24+1, e.NumberOfPeople)
+}
+
+func TestFixture(t *testing.T) {
+	t.Parallel()
+
+	v := bar.NewPropertyValuesFixture(bar.WithDraft(true), bar.WithNumberOfPeople(42))
+	assert.True(t, v.Draft)
+	assert.Equal(t, 42, v.NumberOfPeople)
+	assert.NotEmpty(t, v.Name.Content())
+
+	assert.Equal(t, v, bar.NewPropertyValuesFixture(bar.WithDraft(true), bar.WithNumberOfPeople(42)))
+
+	p := bar.NewPageFixture(bar.WithDraft(true), bar.WithNumberOfPeople(42))
+	assert.Equal(t, v, bar.GetPropertyValues(p.Properties))
+}
+
+func TestRandomPropertyValues(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 1000; i++ {
+		v := bar.RandomPropertyValues(r)
+		require.NoError(t, v.Validate(), v)
+	}
+
+	assert.NoError(t, bar.NewPropertyValuesFixture().Validate())
+}
+
+func TestEntry_Rollups(t *testing.T) {
+	t.Parallel()
+
+	p := database.Page{}
+	require.NoError(t, json.Unmarshal([]byte(`{"object":"page","id":"page","properties":{
+		"Name":{"id":"title","type":"title","title":[]},
+		"Total Budget":{"id":"a","type":"rollup","rollup":{"type":"number","number":12.5,"function":"sum"}},
+		"All Views":{"id":"b","type":"rollup","rollup":{"type":"array","array":[
+			{"type":"number","number":3},{"type":"number","number":4}
+		],"function":"show_original"}}
+	}}`), &p))
+
+	e := bar.NewExactEntry(p)
+	assert.Equal(t, 12.5, e.TotalBudget)
+	assert.Equal(t, []float64{3, 4}, e.AllViews)
+
+	// rollups are read-only
+	e.TotalBudget = 0
+	assert.Empty(t, e.Changes())
+	assert.NotContains(t, e.ToPropertyValueMap(), "Total Budget")
+}
+
+func TestRepository_Expand(t *testing.T) {
+	t.Parallel()
+
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	srv.AddDatabase(foo.DatabaseID, "Foo", foo.Properties(true))
+
+	// the entries were generated with the properties blub gives bar databases
+	props := notion.PropertyMetaMap{"Labels": {Type: notion.PropertyTypeMultiSelect}}
+	for name, meta := range bar.Properties {
+		props[name] = meta
+	}
+
+	db := srv.AddDatabase("", "Bar", props)
+
+	cli, err := srv.Client()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	related, err := foo.NewRepositoryWithClient(cli, notion.Id(foo.DatabaseID)).Create(ctx, foo.PropertyValues{
+		Title: notion.NewRichTexts("Hello"), Stage: foo.StageInProgress, Views: 1<<24 + 1,
+	})
+	require.NoError(t, err)
+
+	repo := bar.NewRepositoryWithClient(cli, notion.Id(db.Id))
+
+	e, err := repo.Create(ctx, bar.PropertyValues{Name: notion.NewRichTexts("Alice"), RelatedTo: []foo.ID{related.ID}})
+	require.NoError(t, err)
+
+	g, err := repo.Expand(ctx, []bar.Entry{e}, 1)
+	require.NoError(t, err)
+
+	// entries in the graph keep what notion.Page can't hold
+	entries := e.RelatedToIn(g)
+	require.Len(t, entries, 1)
+	assert.Equal(t, foo.StageInProgress, entries[0].Stage)
+	assert.Equal(t, int64(1<<24+1), entries[0].Views)
+	assert.Empty(t, entries[0].Changes())
+}
+
+func TestEntry_Validate(t *testing.T) {
+	t.Parallel()
+
+	p := database.Page{}
+	require.NoError(t, json.Unmarshal([]byte(`{"object":"page","id":"page","properties":{
+		"Name":{"id":"title","type":"title","title":[{"type":"text","text":{"content":"Alice"},"plain_text":"Alice"}]},
+		"Number Of People":{"type":"number","number":1.5}
+	}}`), &p))
+
+	// the fraction is cut off when reading the page
+	e := bar.NewExactEntry(p)
+	assert.Equal(t, 1, e.NumberOfPeople)
+	assert.NoError(t, e.PropertyValues.Validate())
+	assert.EqualError(t, e.Validate(), "invalid property values: Number Of People holds 1.5, which is no integer")
+
+	e.NumberOfPeople = 2
+	assert.NoError(t, e.Validate())
+}
+
+func TestRepository_PropertyNames(t *testing.T) {
+	t.Parallel()
+
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	// the entries were generated with the properties blub gives bar databases
+	props := notion.PropertyMetaMap{"Labels": {Type: notion.PropertyTypeMultiSelect}}
+	for name, meta := range bar.Properties {
+		props[name] = meta
+	}
+
+	db := srv.AddDatabase("", "Bar", props)
+
+	cli, err := srv.Client()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	repo := bar.NewRepositoryWithClient(cli, notion.Id(db.Id))
+
+	for i, name := range []string{"Alice", "Bob"} {
+		_, err := repo.Create(ctx, bar.PropertyValues{Name: notion.NewRichTexts(name), NumberOfPeople: 5 + i})
+		require.NoError(t, err)
+	}
+
+	byPeople := &notion.Sorts{{Property: bar.PropNumberOfPeople, Direction: notion.SortDirectionDescending}}
+	assert.Equal(t, []string{"Bob", "Alice"}, all(t, repo.Query(nil, byPeople)))
+
+	e, err := repo.Query(nil, byPeople).Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 6, e.NumberOfPeople)
+}
@@ -0,0 +1,93 @@
+// Code generated by go-notion-codegen v0.0.2 from schema 97e364c87aa66c1cd393cc8b72b492a3293f9349b8ac81f22f3032bde895bfef with generator b20b9e0aa911b4941c2287355a392295620c76b002080214779e2d185414b799; DO NOT EDIT.
+
+package bar
+
+import (
+	"math/rand"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion-codegen/example/databases/foo"
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// FixtureOption changes the property values of a fixture.
+type FixtureOption func(*PropertyValues)
+
+// WithCategory sets "Category" of a fixture.
+func WithCategory(v notion.SelectValue) FixtureOption {
+	return func(f *PropertyValues) { f.Category = v }
+}
+
+// WithDescription sets "Description" of a fixture.
+func WithDescription(v notion.RichTexts) FixtureOption {
+	return func(f *PropertyValues) { f.Description = v }
+}
+
+// WithDraft sets "Draft" of a fixture.
+func WithDraft(v bool) FixtureOption {
+	return func(f *PropertyValues) { f.Draft = v }
+}
+
+// WithExpires sets "Expires" of a fixture.
+func WithExpires(v database.DateValue) FixtureOption {
+	return func(f *PropertyValues) { f.Expires = v }
+}
+
+// WithLabels sets "Labels" of a fixture.
+func WithLabels(v notion.PropertyOptions) FixtureOption {
+	return func(f *PropertyValues) { f.Labels = v }
+}
+
+// WithName sets "Name" of a fixture.
+func WithName(v notion.RichTexts) FixtureOption {
+	return func(f *PropertyValues) { f.Name = v }
+}
+
+// WithNumberOfPeople sets "Number Of People" of a fixture.
+func WithNumberOfPeople(v int) FixtureOption {
+	return func(f *PropertyValues) { f.NumberOfPeople = v }
+}
+
+// WithRelatedTo sets "Related To" of a fixture.
+func WithRelatedTo(v []foo.ID) FixtureOption {
+	return func(f *PropertyValues) { f.RelatedTo = v }
+}
+
+// WithResources sets "Resources" of a fixture.
+func WithResources(v notion.Files) FixtureOption {
+	return func(f *PropertyValues) { f.Resources = v }
+}
+
+// NewPropertyValuesFixture returns valid property values to be used in tests,
+// unless the schema requires relations or files, which have to be set with options.
+// The values are random, but the same on every call, and can be changed with options.
+func NewPropertyValuesFixture(opts ...FixtureOption) PropertyValues {
+	v := RandomPropertyValues(rand.New(rand.NewSource(1))) //nolint:gosec // fixtures do not need to be secure
+
+	for _, opt := range opts {
+		opt(&v)
+	}
+
+	return v
+}
+
+// NewPageFixture returns a page with the property values of a fixture.
+func NewPageFixture(opts ...FixtureOption) notion.Page {
+	return database.NewPage(NewPropertyValuesFixture(opts...).ToPropertyValueMap())
+}
+
+// RandomPropertyValues returns random property values.
+// Selects only use the options of the schema and whole numbers are generated for int properties.
+// The values fit the constraints, except that required relations and files stay empty.
+func RandomPropertyValues(r *rand.Rand, opts ...database.RandomOption) PropertyValues {
+	return PropertyValues{
+		Category:       database.RandomSelect(r),
+		Description:    database.Truncate(database.RandomText(r, opts...), 20),
+		Draft:          r.Intn(2) == 1,
+		Expires:        database.RandomDate(r, opts...),
+		Labels:         database.RandomMultiSelect(r),
+		Name:           database.RandomMatch(r, namePattern, 30),
+		NumberOfPeople: int(database.RandomBetween(r, 0, 1000, 0)),
+		RelatedTo:      []foo.ID{},
+	}
+}
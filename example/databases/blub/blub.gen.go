@@ -1,29 +1,186 @@
+// Code generated by go-notion-codegen v0.0.2 from schema 14473167bf1d4ba18a425a02edc03f1673987ff8b9f6bf1ae92be86ba64c2904 with generator b20b9e0aa911b4941c2287355a392295620c76b002080214779e2d185414b799; DO NOT EDIT.
+
 package blub
 
-import "github.com/faetools/go-notion/pkg/notion"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion-codegen/example/databases/foo"
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// The names of the properties in Notion.
+const (
+	PropCategory       = "Category"
+	PropDescription    = "Description"
+	PropDraft          = "Draft"
+	PropExpires        = "Expires"
+	PropLabels         = "Labels"
+	PropName           = "Name"
+	PropNumberOfPeople = "Number Of People"
+	PropRelatedTo      = "Related To"
+	PropResources      = "Resources"
+)
+
+// The IDs of the properties in Notion, which don't change when the properties are renamed.
+const (
+	PropNameID = "title"
+)
+
+// schema holds the properties of the database the package was generated from.
+const schema = `{
+	"Category": {
+		"id": "",
+		"name": "",
+		"select": {
+			"options": []
+		},
+		"type": "select"
+	},
+	"Description": {
+		"id": "",
+		"name": "",
+		"rich_text": {},
+		"type": "rich_text"
+	},
+	"Draft": {
+		"checkbox": {},
+		"id": "",
+		"name": "",
+		"type": "checkbox"
+	},
+	"Expires": {
+		"date": {},
+		"id": "",
+		"name": "",
+		"type": "date"
+	},
+	"Labels": {
+		"id": "",
+		"multi_select": {
+			"options": []
+		},
+		"name": "",
+		"type": "multi_select"
+	},
+	"Name": {
+		"id": "title",
+		"name": "Name",
+		"title": {},
+		"type": "title"
+	},
+	"Number Of People": {
+		"id": "",
+		"name": "",
+		"number": {
+			"format": "number"
+		},
+		"type": "number"
+	},
+	"Related To": {
+		"id": "",
+		"name": "",
+		"relation": {
+			"database_id": "5f3a9c8e-1d2b-4c6a-9e7f-0a1b2c3d4e5f",
+			"synced_property_name": ""
+		},
+		"type": "relation"
+	},
+	"Resources": {
+		"files": {},
+		"id": "",
+		"name": "",
+		"type": "files"
+	}
+}`
+
+// Schema returns the properties of the database the package was generated from.
+func Schema() notion.PropertyMetaMap {
+	props := notion.PropertyMetaMap{}
+	if err := json.Unmarshal([]byte(schema), &props); err != nil {
+		panic(err)
+	}
+
+	return props
+}
 
 type PropertyValues struct {
-	Category       notion.SelectValue
+	Category       string
 	Description    notion.RichTexts
 	Draft          bool
-	Expires        notion.Date
-	Labels         notion.PropertyOptions
-	Name           notion.RichTexts
+	Expires        time.Time
+	Labels         []string
+	Name           string
 	NumberOfPeople int
-	RelatedTo      notion.References
+	RelatedTo      []foo.ID
 	Resources      notion.Files
 }
 
 func GetPropertyValues(props notion.PropertyValueMap) PropertyValues {
+	return GetExactPropertyValues(props, nil, nil)
+}
+
+// GetExactPropertyValues returns the property values, using the exact numbers
+// and the raw values notion.PropertyValue can't hold, where they are given.
+func GetExactPropertyValues(props notion.PropertyValueMap, nums database.Numbers, raw database.RawValues) PropertyValues {
 	return PropertyValues{
-		Category:       props["Category"].GetSelect(),
+		Category:       props["Category"].GetSelect().Name,
 		Description:    props["Description"].GetRichText(),
 		Draft:          props["Draft"].GetCheckbox(),
-		Expires:        props["Expires"].GetDate(),
-		Labels:         props["Labels"].GetMultiSelect(),
-		Name:           props["Name"].GetTitle(),
-		NumberOfPeople: int(props["Number Of People"].GetNumber()),
-		RelatedTo:      props["Related To"].GetRelation(),
+		Expires:        props["Expires"].GetDate().Start,
+		Labels:         props["Labels"].GetMultiSelect().GetNames(),
+		Name:           props["Name"].GetTitle().Content(),
+		NumberOfPeople: int(database.Int64(props["Number Of People"], nums["Number Of People"])),
+		RelatedTo:      database.IDs[foo.ID](props["Related To"].GetRelation()),
 		Resources:      props["Resources"].GetFiles(),
 	}
 }
+
+// ToPropertyValueMap returns the property values as a property value map.
+func (v PropertyValues) ToPropertyValueMap() notion.PropertyValueMap {
+	return notion.PropertyValueMap{
+		"Category":         {Type: notion.PropertyTypeSelect, Select: database.SelectName(v.Category)},
+		"Description":      {Type: notion.PropertyTypeRichText, RichText: &v.Description},
+		"Draft":            {Type: notion.PropertyTypeCheckbox, Checkbox: &v.Draft},
+		"Expires":          {Type: notion.PropertyTypeDate, Date: database.Time(v.Expires)},
+		"Labels":           {Type: notion.PropertyTypeMultiSelect, MultiSelect: database.Options(v.Labels)},
+		"Name":             {Id: "title", Type: notion.PropertyTypeTitle, Title: database.Text(v.Name)},
+		"Number Of People": {Type: notion.PropertyTypeNumber, Number: database.Number(v.NumberOfPeople)},
+		"Related To":       {Type: notion.PropertyTypeRelation, Relation: database.References(v.RelatedTo)},
+		"Resources":        {Type: notion.PropertyTypeFiles, Files: &v.Resources},
+	}
+}
+
+// ToNumbers returns the exact numbers of the property values.
+func (v PropertyValues) ToNumbers() database.Numbers {
+	return database.Numbers{
+		"Number Of People": database.Int64Number(int64(v.NumberOfPeople)),
+	}
+}
+
+// ToRawValues returns the raw values of the property values notion.PropertyValue can't hold.
+func (v PropertyValues) ToRawValues() database.RawValues {
+	return database.RawValues{}
+}
+
+// Diff returns the property values of to that differ from those of from,
+// as a property value map that can be used to update a page.
+func Diff(from, to PropertyValues) notion.PropertyValueMap {
+	return database.DiffRaw(from.ToPropertyValueMap(), to.ToPropertyValueMap(),
+		from.ToNumbers(), to.ToNumbers(), from.ToRawValues(), to.ToRawValues())
+}
+
+// Validate checks that the property values fit the schema of the database and its constraints.
+func (v PropertyValues) Validate() error {
+	val := &database.Validator{}
+	v.validate(val)
+
+	return val.Err()
+}
+
+// validate adds the reasons why the property values are invalid to the validator.
+func (v PropertyValues) validate(val *database.Validator) {
+	val.Int("Number Of People", v.NumberOfPeople)
+}
@@ -0,0 +1,33 @@
+package blub_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/faetools/go-notion-codegen/example/databases/blub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlainValues(t *testing.T) {
+	t.Parallel()
+
+	v := blub.NewPropertyValuesFixture(blub.WithName("Hello"), blub.WithLabels([]string{}))
+	assert.Equal(t, v, blub.GetPropertyValues(v.ToPropertyValueMap()))
+
+	ctx := context.Background()
+	store := blub.NewFake()
+
+	e, err := store.Create(ctx, v)
+	require.NoError(t, err)
+
+	e.Name = "World"
+	e.Labels = append(e.Labels, "New")
+	assert.Len(t, e.Changes(), 2)
+
+	e, err = store.Update(ctx, e)
+	require.NoError(t, err)
+	assert.Equal(t, "World", e.Page.Properties["Name"].GetTitle().Content())
+	assert.Equal(t, []string{"New"}, e.Labels)
+	assert.Empty(t, e.Changes())
+}
@@ -0,0 +1,123 @@
+// Code generated by go-notion-codegen v0.0.2 from schema 14473167bf1d4ba18a425a02edc03f1673987ff8b9f6bf1ae92be86ba64c2904 with generator b20b9e0aa911b4941c2287355a392295620c76b002080214779e2d185414b799; DO NOT EDIT.
+
+package blub
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion-codegen/example/databases/foo"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/spf13/afero"
+)
+
+// ID is the ID of a blub entry.
+type ID notion.UUID
+
+// Entry is an entry of a blub database.
+type Entry struct {
+	ID   ID
+	Page notion.Page
+	// Numbers are the exact numbers of the page, if they are known.
+	Numbers database.Numbers
+	// Raw are the values of the page notion.Page can't hold, e.g. the results of formulas.
+	Raw database.RawValues
+	PropertyValues
+}
+
+// NewEntry returns the entry of the given page.
+func NewEntry(p notion.Page) Entry {
+	return NewExactEntry(database.Page{Page: p})
+}
+
+// NewExactEntry returns the entry of the given page, using its exact numbers and raw values.
+func NewExactEntry(p database.Page) Entry {
+	return Entry{
+		ID:             ID(p.Id),
+		Page:           p.Page,
+		Numbers:        p.Numbers,
+		Raw:            p.Raw,
+		PropertyValues: GetExactPropertyValues(p.Properties, p.Numbers, p.Raw),
+	}
+}
+
+// exactPage returns the page of the entry with its exact numbers and raw values.
+func (e Entry) exactPage() database.Page {
+	return database.Page{Page: e.Page, Numbers: e.Numbers, Raw: e.Raw}
+}
+
+// Changes returns the property values that were changed since the page of the entry was loaded.
+func (e Entry) Changes() notion.PropertyValueMap {
+	return Diff(GetExactPropertyValues(e.Page.Properties, e.Numbers, e.Raw), e.PropertyValues)
+}
+
+// Validate checks the property values like PropertyValues.Validate and that the whole numbers
+// read from the page had no fraction, which reading them cut off, unless they were changed since.
+func (e Entry) Validate() error {
+	val := &database.Validator{}
+	e.PropertyValues.validate(val)
+
+	nums := e.Numbers
+	val.Integral("Number Of People", nums["Number Of People"], int64(e.NumberOfPeople))
+
+	return val.Err()
+}
+
+// DownloadResources downloads the files of "Resources" into the directory
+// and returns a manifest of them. Expired URLs are refreshed through the client.
+func (e Entry) DownloadResources(ctx context.Context, cli *notion.Client, fs afero.Fs, dir string,
+	opts ...database.DownloadOption,
+) (database.Manifest, error) {
+	return database.DownloadFiles(ctx, cli, fs, dir, notion.Id(e.ID), "Resources", e.Resources, opts...)
+}
+
+// GetEntry returns the entry with the given ID.
+func GetEntry(ctx context.Context, cli *notion.Client, id ID) (Entry, error) {
+	p, err := database.GetPage(ctx, cli, notion.Id(id))
+	if err != nil {
+		return Entry{}, fmt.Errorf("getting blub entry %s: %w", id, err)
+	}
+
+	return NewExactEntry(*p), nil
+}
+
+// Query returns an iterator over the entries of the database that match the filter,
+// in the order given by the sorts.
+func Query(cli *notion.Client, id notion.Id, filter *notion.Filter, sorts *notion.Sorts) *database.Iterator[Entry] {
+	return database.NewExactIterator(cli, id, filter, sorts, NewExactEntry)
+}
+
+// Expand returns a graph of the entries and the pages they relate to, up to the given depth.
+func Expand(ctx context.Context, cli *notion.Client, entries []Entry, depth int,
+	opts ...database.ExpandOption,
+) (*database.Graph, error) {
+	pages := make([]database.Page, len(entries))
+
+	for i, e := range entries {
+		pages[i] = e.exactPage()
+	}
+
+	return database.Expand(ctx, cli, pages, depth, opts...)
+}
+
+// RelatedToEntries returns the entries "Related To" relates to.
+func (v PropertyValues) RelatedToEntries(ctx context.Context, cli *notion.Client) ([]foo.Entry, error) {
+	entries := make([]foo.Entry, len(v.RelatedTo))
+
+	for i, id := range v.RelatedTo {
+		e, err := foo.GetEntry(ctx, cli, id)
+		if err != nil {
+			return nil, err
+		}
+
+		entries[i] = e
+	}
+
+	return entries, nil
+}
+
+// RelatedToIn returns the entries "Related To" relates to that are part of the graph.
+func (v PropertyValues) RelatedToIn(g *database.Graph) []foo.Entry {
+	return database.Related(g, v.RelatedTo, foo.NewExactEntry)
+}
@@ -0,0 +1,112 @@
+// Code generated by go-notion-codegen v0.0.2 from schema e600c08eb03cc960d86279de18bfeafa5a515c722d8bc851e538c2d84796247a with generator b20b9e0aa911b4941c2287355a392295620c76b002080214779e2d185414b799; DO NOT EDIT.
+
+package foo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// ID is the ID of a foo entry.
+type ID notion.UUID
+
+// Entry is an entry of a foo database.
+type Entry struct {
+	ID   ID
+	Page notion.Page
+	// Numbers are the exact numbers of the page, if they are known.
+	Numbers database.Numbers
+	// Raw are the values of the page notion.Page can't hold, e.g. the results of formulas.
+	Raw database.RawValues
+	PropertyValues
+}
+
+// NewEntry returns the entry of the given page.
+func NewEntry(p notion.Page) Entry {
+	return NewExactEntry(database.Page{Page: p})
+}
+
+// NewExactEntry returns the entry of the given page, using its exact numbers and raw values.
+func NewExactEntry(p database.Page) Entry {
+	return Entry{
+		ID:             ID(p.Id),
+		Page:           p.Page,
+		Numbers:        p.Numbers,
+		Raw:            p.Raw,
+		PropertyValues: GetExactPropertyValues(p.Properties, p.Numbers, p.Raw),
+	}
+}
+
+// exactPage returns the page of the entry with its exact numbers and raw values.
+func (e Entry) exactPage() database.Page {
+	return database.Page{Page: e.Page, Numbers: e.Numbers, Raw: e.Raw}
+}
+
+// Changes returns the property values that were changed since the page of the entry was loaded.
+func (e Entry) Changes() notion.PropertyValueMap {
+	return Diff(GetExactPropertyValues(e.Page.Properties, e.Numbers, e.Raw), e.PropertyValues)
+}
+
+// Validate checks the property values like PropertyValues.Validate and that the whole numbers
+// read from the page had no fraction, which reading them cut off, unless they were changed since.
+func (e Entry) Validate() error {
+	val := &database.Validator{}
+	e.PropertyValues.validate(val)
+
+	_, nums, _ := database.ByID(propertyIDs, e.Page.Properties, e.Numbers, e.Raw)
+	val.Integral("Views", nums["Views"], e.Views)
+
+	return val.Err()
+}
+
+// GetEntry returns the entry with the given ID.
+func GetEntry(ctx context.Context, cli *notion.Client, id ID) (Entry, error) {
+	p, err := database.GetPage(ctx, cli, notion.Id(id))
+	if err != nil {
+		return Entry{}, fmt.Errorf("getting foo entry %s: %w", id, err)
+	}
+
+	return NewExactEntry(*p), nil
+}
+
+// Query returns an iterator over the entries of the database that match the filter,
+// in the order given by the sorts.
+func Query(cli *notion.Client, id notion.Id, filter *notion.Filter, sorts *notion.Sorts) *database.Iterator[Entry] {
+	return database.NewExactIterator(cli, id, filter, sorts, NewExactEntry)
+}
+
+// Expand returns a graph of the entries and the pages they relate to, up to the given depth.
+func Expand(ctx context.Context, cli *notion.Client, entries []Entry, depth int,
+	opts ...database.ExpandOption,
+) (*database.Graph, error) {
+	pages := make([]database.Page, len(entries))
+
+	for i, e := range entries {
+		pages[i] = e.exactPage()
+	}
+
+	return database.Expand(ctx, cli, pages, depth, opts...)
+}
+
+// AssigneesNames returns the names of the users of "Assignees", as the directory knows them.
+func (v PropertyValues) AssigneesNames(ctx context.Context, dir *database.Directory) ([]string, error) {
+	return dir.Names(ctx, v.Assignees)
+}
+
+// AssigneesEmails returns the emails of the users of "Assignees" the directory knows.
+func (v PropertyValues) AssigneesEmails(ctx context.Context, dir *database.Directory) ([]string, error) {
+	return dir.Emails(ctx, v.Assignees)
+}
+
+// CreatedByName returns the name of the user of "Created By", as the directory knows it.
+func (v PropertyValues) CreatedByName(ctx context.Context, dir *database.Directory) (string, error) {
+	return dir.Name(ctx, v.CreatedBy)
+}
+
+// CreatedByEmail returns the email of the user of "Created By", if the directory knows it.
+func (v PropertyValues) CreatedByEmail(ctx context.Context, dir *database.Directory) (string, error) {
+	return dir.Email(ctx, v.CreatedBy)
+}
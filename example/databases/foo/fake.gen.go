@@ -0,0 +1,206 @@
+// Code generated by go-notion-codegen v0.0.2 from schema e600c08eb03cc960d86279de18bfeafa5a515c722d8bc851e538c2d84796247a with generator b20b9e0aa911b4941c2287355a392295620c76b002080214779e2d185414b799; DO NOT EDIT.
+
+package foo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion-codegen/query"
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// Fake holds foo entries in memory, so that it can be used instead of a Repository in tests.
+type Fake struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewFake returns a fake holding the given entries.
+func NewFake(entries ...Entry) *Fake {
+	return &Fake{entries: entries}
+}
+
+// Get returns the entry with the given ID.
+func (f *Fake) Get(_ context.Context, id ID) (Entry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	i, err := f.index(id)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	return f.entries[i], nil
+}
+
+// Query returns an iterator over the entries that are not archived and match the filter,
+// in the order given by the sorts.
+func (f *Fake) Query(filter *notion.Filter, sorts *notion.Sorts) *database.Iterator[Entry] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	pages := []database.Page{}
+	byID := map[notion.UUID]Entry{}
+
+	for _, e := range f.entries {
+		if e.Page.Archived {
+			continue
+		}
+
+		pages = append(pages, e.exactPage())
+		byID[e.Page.Id] = e
+	}
+
+	pages = query.ApplyExact(pages, filter, sorts, query.WithSchema(Schema()), query.WithStatuses(statusOptions))
+	entries := make([]Entry, len(pages))
+
+	for i, p := range pages {
+		entries[i] = byID[p.Id]
+	}
+
+	return database.NewSliceIterator(entries)
+}
+
+// Create creates an entry with the given property values, if they are valid.
+func (f *Fake) Create(_ context.Context, v PropertyValues) (Entry, error) {
+	if err := v.Validate(); err != nil {
+		return Entry{}, fmt.Errorf("creating foo entry: %w", err)
+	}
+
+	e := NewExactEntry(database.Page{
+		Page:    database.NewPage(v.ToPropertyValueMap()),
+		Numbers: v.ToNumbers(),
+		Raw:     v.ToRawValues(),
+	})
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.entries = append(f.entries, e)
+
+	return e, nil
+}
+
+// Update saves the property values of the entry that were changed since its page was loaded,
+// if they are valid.
+func (f *Fake) Update(_ context.Context, e Entry) (Entry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.update(e, nil)
+}
+
+// UpdateIfUnchanged is like Update, but returns a *database.ConflictError
+// if the entry was edited after its page was loaded.
+func (f *Fake) UpdateIfUnchanged(_ context.Context, e Entry, opts ...database.UpdateOption) (Entry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.update(e, func(current database.Page, changes notion.PropertyValueMap) error {
+		return database.CheckConflict(e.exactPage(), current, changes, opts...)
+	})
+}
+
+// update saves the changes of the entry, if the check allows it.
+// Like a repository, it returns an entry without changes as it is.
+func (f *Fake) update(e Entry, check func(current database.Page, changes notion.PropertyValueMap) error) (Entry, error) {
+	changes := e.Changes()
+	if len(changes) == 0 {
+		return e, nil
+	}
+
+	i, err := f.index(e.ID)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	if err := e.Validate(); err != nil {
+		return Entry{}, fmt.Errorf("updating foo entry %s: %w", e.ID, err)
+	}
+
+	stored := &f.entries[i]
+
+	if check != nil {
+		if err := check(stored.exactPage(), changes); err != nil {
+			return Entry{}, err
+		}
+	}
+
+	props := notion.PropertyValueMap{}
+
+	for key, v := range stored.Page.Properties {
+		props[key] = v
+	}
+
+	for key, v := range changes {
+		props[key] = v
+	}
+
+	nums := database.Numbers{}
+
+	for key, n := range stored.Numbers {
+		nums[key] = n
+	}
+
+	for key, n := range e.ToNumbers() {
+		if _, ok := changes[key]; ok {
+			nums[key] = n
+		}
+	}
+
+	raw := database.RawValues{}
+
+	for key, v := range stored.Raw {
+		raw[key] = v
+	}
+
+	for key, v := range e.ToRawValues() {
+		if _, ok := changes[key]; ok {
+			raw[key] = v
+		}
+	}
+
+	stored.Page.Properties = props
+	stored.Page.LastEditedTime = time.Now().UTC()
+	stored.Numbers = nums
+	stored.Raw = raw
+	stored.PropertyValues = GetExactPropertyValues(props, nums, raw)
+
+	return *stored, nil
+}
+
+// Archive archives the entry with the given ID.
+func (f *Fake) Archive(_ context.Context, id ID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	i, err := f.index(id)
+	if err != nil {
+		return err
+	}
+
+	f.entries[i].Page.Archived = true
+	f.entries[i].Page.LastEditedTime = time.Now().UTC()
+
+	return nil
+}
+
+// index returns the index of the entry with the given ID.
+func (f *Fake) index(id ID) (int, error) {
+	for i, e := range f.entries {
+		if database.NormalizeID(notion.UUID(e.ID)) == database.NormalizeID(notion.UUID(id)) {
+			return i, nil
+		}
+	}
+
+	return 0, &notion.Error{
+		Status:  http.StatusNotFound,
+		Code:    "object_not_found",
+		Message: fmt.Sprintf("Could not find page with ID: %s.", id),
+	}
+}
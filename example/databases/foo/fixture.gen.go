@@ -0,0 +1,81 @@
+// Code generated by go-notion-codegen v0.0.2 from schema e600c08eb03cc960d86279de18bfeafa5a515c722d8bc851e538c2d84796247a with generator b20b9e0aa911b4941c2287355a392295620c76b002080214779e2d185414b799; DO NOT EDIT.
+
+package foo
+
+import (
+	"math/rand"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// FixtureOption changes the property values of a fixture.
+type FixtureOption func(*PropertyValues)
+
+// WithBudget sets "Budget" of a fixture.
+func WithBudget(v database.Money) FixtureOption {
+	return func(f *PropertyValues) { f.Budget = v }
+}
+
+// WithImportant sets "Important" of a fixture.
+func WithImportant(v bool) FixtureOption {
+	return func(f *PropertyValues) { f.Important = v }
+}
+
+// WithProgress sets "Progress" of a fixture.
+func WithProgress(v database.Percent) FixtureOption {
+	return func(f *PropertyValues) { f.Progress = v }
+}
+
+// WithStage sets "Stage" of a fixture.
+func WithStage(v StageOption) FixtureOption {
+	return func(f *PropertyValues) { f.Stage = v }
+}
+
+// WithSummary sets "Summary" of a fixture.
+func WithSummary(v notion.RichTexts) FixtureOption {
+	return func(f *PropertyValues) { f.Summary = v }
+}
+
+// WithTitle sets "Title" of a fixture.
+func WithTitle(v notion.RichTexts) FixtureOption {
+	return func(f *PropertyValues) { f.Title = v }
+}
+
+// WithViews sets "Views" of a fixture.
+func WithViews(v int64) FixtureOption {
+	return func(f *PropertyValues) { f.Views = v }
+}
+
+// NewPropertyValuesFixture returns valid property values to be used in tests,
+// unless the schema requires relations or files, which have to be set with options.
+// The values are random, but the same on every call, and can be changed with options.
+func NewPropertyValuesFixture(opts ...FixtureOption) PropertyValues {
+	v := RandomPropertyValues(rand.New(rand.NewSource(1))) //nolint:gosec // fixtures do not need to be secure
+
+	for _, opt := range opts {
+		opt(&v)
+	}
+
+	return v
+}
+
+// NewPageFixture returns a page with the property values of a fixture.
+func NewPageFixture(opts ...FixtureOption) notion.Page {
+	return database.NewPage(NewPropertyValuesFixture(opts...).ToPropertyValueMap())
+}
+
+// RandomPropertyValues returns random property values.
+// Selects only use the options of the schema and whole numbers are generated for int properties.
+// The values fit the constraints, except that required relations and files stay empty.
+func RandomPropertyValues(r *rand.Rand, opts ...database.RandomOption) PropertyValues {
+	return PropertyValues{
+		Budget:    database.RandomMoney(r, "EUR"),
+		Important: r.Intn(2) == 1,
+		Progress:  database.RandomPercent(r),
+		Stage:     StageOption(database.RandomSelect(r, "Not started", "In progress", "Done").Name),
+		Summary:   database.RandomText(r, opts...),
+		Title:     database.RandomText(r, opts...),
+		Views:     r.Int63n(1000),
+	}
+}
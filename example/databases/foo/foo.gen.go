@@ -1,17 +1,240 @@
+// Code generated by go-notion-codegen v0.0.2 from schema e600c08eb03cc960d86279de18bfeafa5a515c722d8bc851e538c2d84796247a with generator b20b9e0aa911b4941c2287355a392295620c76b002080214779e2d185414b799; DO NOT EDIT.
+
 package foo
 
-import "github.com/faetools/go-notion/pkg/notion"
+import (
+	"encoding/json"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// The names of the properties in Notion.
+const (
+	PropAssignees = "Assignees"
+	PropBudget    = "Budget"
+	PropCreatedBy = "Created By"
+	PropImportant = "Important"
+	PropProgress  = "Progress"
+	PropScore     = "Score"
+	PropStage     = "Stage"
+	PropSummary   = "Summary"
+	PropTitle     = "Title"
+	PropViews     = "Views"
+)
+
+// The IDs of the properties in Notion, which don't change when the properties are renamed.
+const (
+	PropTitleID = "title"
+)
+
+// StageOption is an option of "Stage".
+type StageOption string
+
+// The options of "Stage".
+const (
+	StageNotStarted StageOption = "Not started"
+	StageInProgress StageOption = "In progress"
+	StageDone       StageOption = "Done"
+)
+
+// StageGroup is a group of options of "Stage".
+type StageGroup string
+
+// The groups of options of "Stage".
+const (
+	StageGroupToDo       StageGroup = "To-do"
+	StageGroupInProgress StageGroup = "In progress"
+	StageGroupComplete   StageGroup = "Complete"
+)
+
+// Group returns the group the option belongs to.
+func (o StageOption) Group() StageGroup {
+	switch o {
+	case StageNotStarted:
+		return StageGroupToDo
+	case StageInProgress:
+		return StageGroupInProgress
+	case StageDone:
+		return StageGroupComplete
+	default:
+		return ""
+	}
+}
+
+// propertyIDs are the IDs of the properties by name,
+// which property values are found by and written with.
+var propertyIDs = map[string]string{
+	PropTitle: PropTitleID,
+}
+
+// schema holds the properties of the database the package was generated from.
+const schema = `{
+	"Assignees": {
+		"id": "",
+		"name": "",
+		"type": "people"
+	},
+	"Budget": {
+		"id": "",
+		"name": "",
+		"number": {
+			"format": "euro"
+		},
+		"type": "number"
+	},
+	"Created By": {
+		"id": "",
+		"name": "",
+		"type": "created_by"
+	},
+	"Important": {
+		"checkbox": {},
+		"id": "",
+		"name": "",
+		"type": "checkbox"
+	},
+	"Progress": {
+		"id": "",
+		"name": "",
+		"number": {
+			"format": "percent"
+		},
+		"type": "number"
+	},
+	"Score": {
+		"id": "",
+		"name": "",
+		"type": "formula"
+	},
+	"Stage": {
+		"id": "",
+		"name": "",
+		"type": "status"
+	},
+	"Summary": {
+		"id": "",
+		"name": "",
+		"rich_text": {},
+		"type": "rich_text"
+	},
+	"Title": {
+		"id": "title",
+		"name": "",
+		"title": {},
+		"type": "title"
+	},
+	"Views": {
+		"id": "",
+		"name": "",
+		"number": {
+			"format": "number_with_commas"
+		},
+		"type": "number"
+	}
+}`
+
+// Schema returns the properties of the database the package was generated from.
+func Schema() notion.PropertyMetaMap {
+	props := notion.PropertyMetaMap{}
+	if err := json.Unmarshal([]byte(schema), &props); err != nil {
+		panic(err)
+	}
+
+	return props
+}
+
+// statusOptions are the options of the statuses the package knows, which the schema can't hold.
+var statusOptions = map[string]database.StatusConfig{
+	PropStage: {Options: []notion.PropertyOption{
+		{Name: "Not started"},
+		{Name: "In progress"},
+		{Name: "Done"},
+	}},
+}
 
 type PropertyValues struct {
+	Assignees []notion.User
+	Budget    database.Money
+	CreatedBy notion.User
 	Important bool
+	Progress  database.Percent
+	Score     float64
+	Stage     StageOption
 	Summary   notion.RichTexts
 	Title     notion.RichTexts
+	Views     int64
 }
 
 func GetPropertyValues(props notion.PropertyValueMap) PropertyValues {
+	return GetExactPropertyValues(props, nil, nil)
+}
+
+// GetExactPropertyValues returns the property values, using the exact numbers
+// and the raw values notion.PropertyValue can't hold, where they are given.
+func GetExactPropertyValues(props notion.PropertyValueMap, nums database.Numbers, raw database.RawValues) PropertyValues {
+	props, nums, raw = database.ByID(propertyIDs, props, nums, raw)
+
 	return PropertyValues{
+		Assignees: database.NewUsers(raw["Assignees"]),
+		Budget:    database.MoneyOf(props["Budget"], nums["Budget"], "EUR"),
+		CreatedBy: database.NewUser(raw["Created By"]),
 		Important: props["Important"].GetCheckbox(),
+		Progress:  database.Percent(database.Float64(props["Progress"], nums["Progress"])),
+		Score:     database.NewFormula(raw["Score"]).Number,
+		Stage:     StageOption(database.NewStatus(raw["Stage"]).Name),
 		Summary:   props["Summary"].GetRichText(),
 		Title:     props["Title"].GetTitle(),
+		Views:     database.Int64(props["Views"], nums["Views"]),
+	}
+}
+
+// ToPropertyValueMap returns the property values as a property value map.
+func (v PropertyValues) ToPropertyValueMap() notion.PropertyValueMap {
+	return notion.PropertyValueMap{
+		"Budget":    {Type: notion.PropertyTypeNumber, Number: database.Number(v.Budget.Float())},
+		"Important": {Type: notion.PropertyTypeCheckbox, Checkbox: &v.Important},
+		"Progress":  {Type: notion.PropertyTypeNumber, Number: database.Number(v.Progress)},
+		"Stage":     {Type: database.PropertyTypeStatus},
+		"Summary":   {Type: notion.PropertyTypeRichText, RichText: &v.Summary},
+		"Title":     {Id: "title", Type: notion.PropertyTypeTitle, Title: &v.Title},
+		"Views":     {Type: notion.PropertyTypeNumber, Number: database.Number(v.Views)},
+	}
+}
+
+// ToNumbers returns the exact numbers of the property values.
+func (v PropertyValues) ToNumbers() database.Numbers {
+	return database.Numbers{
+		"Budget":   v.Budget.Number(),
+		"Progress": database.Float64Number(float64(v.Progress)),
+		"Views":    database.Int64Number(v.Views),
 	}
 }
+
+// ToRawValues returns the raw values of the property values notion.PropertyValue can't hold.
+func (v PropertyValues) ToRawValues() database.RawValues {
+	return database.RawValues{
+		"Stage": database.Status(string(v.Stage)),
+	}
+}
+
+// Diff returns the property values of to that differ from those of from,
+// as a property value map that can be used to update a page.
+func Diff(from, to PropertyValues) notion.PropertyValueMap {
+	return database.DiffRaw(from.ToPropertyValueMap(), to.ToPropertyValueMap(),
+		from.ToNumbers(), to.ToNumbers(), from.ToRawValues(), to.ToRawValues())
+}
+
+// Validate checks that the property values fit the schema of the database and its constraints.
+func (v PropertyValues) Validate() error {
+	val := &database.Validator{}
+	v.validate(val)
+
+	return val.Err()
+}
+
+// validate adds the reasons why the property values are invalid to the validator.
+func (v PropertyValues) validate(val *database.Validator) {
+	val.Currency("Budget", v.Budget, "EUR")
+	val.Option("Stage", string(v.Stage), "Not started", "In progress", "Done")
+}
@@ -1,9 +1,29 @@
 package foo
 
-import "github.com/faetools/go-notion/pkg/notion"
+import (
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// DatabaseID is the ID of the foo database.
+const DatabaseID notion.UUID = "5f3a9c8e-1d2b-4c6a-9e7f-0a1b2c3d4e5f"
 
 var emptyConfig = &map[string]interface{}{}
 
+// Stage holds the options and groups of the "Stage" status.
+var Stage = database.StatusConfig{
+	Options: []notion.PropertyOption{
+		{Id: "not-started", Name: "Not started", Color: "default"},
+		{Id: "in-progress", Name: "In progress", Color: "blue"},
+		{Id: "done", Name: "Done", Color: "green"},
+	},
+	Groups: []database.StatusGroup{
+		{Id: "to-do", Name: "To-do", Color: "gray", OptionIds: []string{"not-started"}},
+		{Id: "in-progress", Name: "In progress", Color: "blue", OptionIds: []string{"in-progress"}},
+		{Id: "complete", Name: "Complete", Color: "green", OptionIds: []string{"done"}},
+	},
+}
+
 // Properties returns the property meta map for foo databases.
 func Properties(expanded bool) notion.PropertyMetaMap {
 	props := notion.PropertyMetaMap{
@@ -19,6 +39,22 @@ func Properties(expanded bool) notion.PropertyMetaMap {
 			Type:     notion.PropertyTypeCheckbox,
 			Checkbox: emptyConfig,
 		}
+		props["Budget"] = notion.PropertyMeta{
+			Type:   notion.PropertyTypeNumber,
+			Number: &notion.NumberConfig{Format: notion.NumberConfigFormatEuro},
+		}
+		props["Progress"] = notion.PropertyMeta{
+			Type:   notion.PropertyTypeNumber,
+			Number: &notion.NumberConfig{Format: notion.NumberConfigFormatPercent},
+		}
+		props["Views"] = notion.PropertyMeta{
+			Type:   notion.PropertyTypeNumber,
+			Number: &notion.NumberConfig{Format: notion.NumberConfigFormatNumberWithCommas},
+		}
+		props["Score"] = notion.PropertyMeta{Type: notion.PropertyTypeFormula}
+		props["Assignees"] = notion.PropertyMeta{Type: notion.PropertyTypePeople}
+		props["Created By"] = notion.PropertyMeta{Type: notion.PropertyTypeCreatedBy}
+		props["Stage"] = notion.PropertyMeta{Type: database.PropertyTypeStatus}
 	}
 
 	return props
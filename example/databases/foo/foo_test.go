@@ -0,0 +1,408 @@
+package foo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion-codegen/example/databases/foo"
+	"github.com/faetools/go-notion-codegen/notiontest"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository(t *testing.T) {
+	t.Parallel()
+
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	srv.AddDatabase(foo.DatabaseID, "Foo", foo.Properties(true))
+
+	cli, err := srv.Client()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	repo := foo.NewRepositoryWithClient(cli, notion.Id(foo.DatabaseID))
+
+	e, err := repo.Create(ctx, foo.PropertyValues{
+		Title:    notion.NewRichTexts("Hello"),
+		Budget:   database.NewMoney(1234.5, "EUR"),
+		Progress: 0.25,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", e.Title.Content())
+	assert.Equal(t, "€1,234.50", e.Budget.String())
+	assert.Equal(t, "25%", e.Progress.String())
+
+	e.Important = true
+
+	_, err = repo.Update(ctx, e)
+	require.NoError(t, err)
+
+	got, err := repo.Get(ctx, e.ID)
+	require.NoError(t, err)
+	assert.True(t, got.Important)
+
+	important := "Important"
+	it := repo.Query(&notion.Filter{Property: &important, Checkbox: &notion.CheckboxFilter{Equals: true}}, nil)
+
+	got, err = it.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, e.ID, got.ID)
+
+	_, err = it.Next(ctx)
+	assert.True(t, errors.Is(err, database.ErrDone))
+
+	require.NoError(t, repo.Archive(ctx, e.ID))
+
+	_, err = repo.Query(nil, nil).Next(ctx)
+	assert.True(t, errors.Is(err, database.ErrDone))
+}
+
+func TestRepository_Update(t *testing.T) {
+	t.Parallel()
+
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	srv.AddDatabase(foo.DatabaseID, "Foo", foo.Properties(true))
+
+	cli, err := srv.Client()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	repo := foo.NewRepositoryWithClient(cli, notion.Id(foo.DatabaseID))
+
+	e, err := repo.Create(ctx, foo.PropertyValues{Title: notion.NewRichTexts("Hello")})
+	require.NoError(t, err)
+	assert.Empty(t, e.Changes())
+
+	// two workers change different properties of the same entry
+	a, b := e, e
+	a.Important = true
+	b.Summary = notion.NewRichTexts("Summary")
+
+	assert.Equal(t, []string{"Important"}, keys(a.Changes()))
+
+	_, err = repo.Update(ctx, a)
+	require.NoError(t, err)
+
+	_, err = repo.Update(ctx, b)
+	require.NoError(t, err)
+
+	got, err := repo.Get(ctx, e.ID)
+	require.NoError(t, err)
+	assert.True(t, got.Important)
+	assert.Equal(t, "Summary", got.Summary.Content())
+	assert.Equal(t, "Hello", got.Title.Content())
+}
+
+func TestRepository_UpdateIfUnchanged(t *testing.T) {
+	t.Parallel()
+
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	srv.AddDatabase(foo.DatabaseID, "Foo", foo.Properties(true))
+
+	cli, err := srv.Client()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	repo := foo.NewRepositoryWithClient(cli, notion.Id(foo.DatabaseID))
+
+	e, err := repo.Create(ctx, foo.PropertyValues{Title: notion.NewRichTexts("Hello")})
+	require.NoError(t, err)
+
+	a, b := e, e
+	a.Important = true
+	b.Summary = notion.NewRichTexts("Summary")
+
+	_, err = repo.UpdateIfUnchanged(ctx, a)
+	require.NoError(t, err)
+
+	// b was loaded before a was saved
+	_, err = repo.UpdateIfUnchanged(ctx, b)
+
+	conflict := &database.ConflictError{}
+	require.True(t, errors.As(err, &conflict))
+	assert.Equal(t, notion.UUID(e.ID), conflict.ID)
+
+	// but its changes don't overlap with those of a
+	_, err = repo.UpdateIfUnchanged(ctx, b, database.MergeChanges())
+	require.NoError(t, err)
+
+	// b is still based on the page before both were saved
+	b.Summary = notion.NewRichTexts("Other")
+
+	// the options are not written to the spare capacity of the caller's slice
+	opts := make([]database.UpdateOption, 1, 4)
+	opts[0] = database.MergeChanges()
+
+	_, err = repo.UpdateIfUnchanged(ctx, b, opts...)
+	require.True(t, errors.As(err, &conflict))
+	assert.Equal(t, []string{"Summary"}, conflict.Properties)
+	assert.Nil(t, opts[:cap(opts)][1])
+
+	got, err := repo.Get(ctx, e.ID)
+	require.NoError(t, err)
+	assert.True(t, got.Important)
+	assert.Equal(t, "Summary", got.Summary.Content())
+}
+
+func TestRepository_ExactNumbers(t *testing.T) {
+	t.Parallel()
+
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	srv.AddDatabase(foo.DatabaseID, "Foo", foo.Properties(true))
+
+	cli, err := srv.Client()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	repo := foo.NewRepositoryWithClient(cli, notion.Id(foo.DatabaseID))
+
+	// a float32 can't hold this number
+	e, err := repo.Create(ctx, foo.PropertyValues{Title: notion.NewRichTexts("Hello"), Views: 16777217})
+	require.NoError(t, err)
+	assert.Equal(t, int64(16777217), e.Views)
+
+	e.Views++
+	assert.Equal(t, []string{"Views"}, keys(e.Changes()))
+
+	_, err = repo.Update(ctx, e)
+	require.NoError(t, err)
+
+	got, err := repo.Get(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(16777218), got.Views)
+	assert.Empty(t, got.Changes())
+
+	got, err = repo.Query(nil, nil).Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(16777218), got.Views)
+
+	// nor these amounts of money
+	got.Budget = database.Money{Amount: 123456789, Currency: "EUR"}
+
+	got, err = repo.Update(ctx, got)
+	require.NoError(t, err)
+	assert.Equal(t, "€1,234,567.89", got.Budget.String())
+	assert.Equal(t, json.Number("1234567.89"), got.ToNumbers()["Budget"])
+}
+
+func TestStore_Sorts(t *testing.T) {
+	t.Parallel()
+
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	srv.AddDatabase(foo.DatabaseID, "Foo", foo.Properties(true))
+	srv.SetStatusOptions(foo.DatabaseID, map[string]database.StatusConfig{foo.PropStage: foo.Stage})
+
+	cli, err := srv.Client()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	for name, store := range map[string]foo.Store{
+		"repository": foo.NewRepositoryWithClient(cli, notion.Id(foo.DatabaseID)),
+		"fake":       foo.NewFake(),
+	} {
+		// a float32 can't tell the last two numbers apart
+		for _, v := range []foo.PropertyValues{
+			{Title: notion.NewRichTexts("more"), Views: 16777217},
+			{Title: notion.NewRichTexts("less"), Views: 16777216, Stage: foo.StageDone},
+			{Title: notion.NewRichTexts("least"), Views: 1, Stage: foo.StageInProgress},
+		} {
+			_, err := store.Create(ctx, v)
+			require.NoError(t, err, name)
+		}
+
+		assert.Equal(t, []string{"least", "less", "more"}, titles(t, store.Query(nil, &notion.Sorts{
+			{Property: foo.PropViews, Direction: notion.SortDirectionAscending},
+		})), name)
+
+		// statuses are sorted by the order of their options and entries without one come last
+		assert.Equal(t, []string{"least", "less", "more"}, titles(t, store.Query(nil, &notion.Sorts{
+			{Property: foo.PropStage, Direction: notion.SortDirectionAscending},
+		})), name)
+	}
+}
+
+// titles returns the titles of all entries of the iterator.
+func titles(t *testing.T, it *database.Iterator[foo.Entry]) []string {
+	t.Helper()
+
+	res := []string{}
+
+	for {
+		e, err := it.Next(context.Background())
+		if errors.Is(err, database.ErrDone) {
+			return res
+		}
+
+		require.NoError(t, err)
+
+		res = append(res, e.Title.Content())
+	}
+}
+
+func TestEntry_Formula(t *testing.T) {
+	t.Parallel()
+
+	p := database.Page{}
+	require.NoError(t, json.Unmarshal([]byte(`{"object":"page","id":"page","properties":{
+		"Title":{"id":"title","type":"title","title":[]},
+		"Score":{"id":"score","type":"formula","formula":{"type":"number","number":4.5}}
+	}}`), &p))
+
+	e := foo.NewExactEntry(p)
+	assert.Equal(t, 4.5, e.Score)
+
+	// formulas are read-only
+	e.Score = 5
+	assert.Empty(t, e.Changes())
+	assert.NotContains(t, e.ToPropertyValueMap(), "Score")
+}
+
+func TestRepository_Status(t *testing.T) {
+	t.Parallel()
+
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	srv.AddDatabase(foo.DatabaseID, "Foo", foo.Properties(true))
+
+	cli, err := srv.Client()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	for name, store := range map[string]foo.Store{
+		"repository": foo.NewRepositoryWithClient(cli, notion.Id(foo.DatabaseID)),
+		"fake":       foo.NewFake(),
+	} {
+		e, err := store.Create(ctx, foo.PropertyValues{Title: notion.NewRichTexts("Hello"), Stage: foo.StageNotStarted})
+		require.NoError(t, err, name)
+		assert.Equal(t, foo.StageNotStarted, e.Stage, name)
+		assert.Equal(t, foo.StageGroupToDo, e.Stage.Group(), name)
+		assert.Empty(t, e.Changes(), name)
+
+		e.Stage = foo.StageDone
+		assert.Equal(t, []string{"Stage"}, keys(e.Changes()), name)
+
+		_, err = store.Update(ctx, e)
+		require.NoError(t, err, name)
+
+		got, err := store.Get(ctx, e.ID)
+		require.NoError(t, err, name)
+		assert.Equal(t, foo.StageDone, got.Stage, name)
+		assert.Equal(t, foo.StageGroupComplete, got.Stage.Group(), name)
+
+		got.Stage = "Archived"
+		_, err = store.Update(ctx, got)
+		assert.ErrorContains(t, err, `Stage has no option "Archived"`, name)
+	}
+}
+
+func TestEntry_People(t *testing.T) {
+	t.Parallel()
+
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	srv.AddUsers(
+		notion.User{Id: "alice", Name: "Alice", Type: notion.UserTypePerson, Person: &notion.Person{Email: "alice@example.com"}},
+		notion.User{Id: "bob", Name: "Bob", Type: notion.UserTypePerson},
+	)
+
+	cli, err := srv.Client()
+	require.NoError(t, err)
+
+	p := database.Page{}
+	require.NoError(t, json.Unmarshal([]byte(`{"object":"page","id":"page","properties":{
+		"Title":{"id":"title","type":"title","title":[]},
+		"Assignees":{"id":"a","type":"people","people":[{"object":"user","id":"alice"},{"object":"user","id":"bob"}]},
+		"Created By":{"id":"b","type":"created_by","created_by":{"object":"user","id":"bob"}}
+	}}`), &p))
+
+	e := foo.NewExactEntry(p)
+	require.Len(t, e.Assignees, 2)
+
+	ctx := context.Background()
+	dir := database.NewDirectory(cli)
+
+	names, err := e.AssigneesNames(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Alice", "Bob"}, names)
+
+	emails, err := e.AssigneesEmails(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice@example.com"}, emails)
+
+	name, err := e.CreatedByName(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, "Bob", name)
+
+	// people are read-only
+	e.Assignees = nil
+	assert.Empty(t, e.Changes())
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, foo.Properties(true), foo.Schema())
+
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	srv.AddDatabase(foo.DatabaseID, "Foo", foo.Properties(true))
+
+	renamed := foo.Properties(true)
+	renamed["Name"] = renamed["Title"]
+	delete(renamed, "Title")
+	renamedDB := srv.AddDatabase("", "Foo", renamed)
+
+	changed := foo.Properties(true)
+	changed["Budget"] = notion.PropertyMeta{
+		Type:   notion.PropertyTypeNumber,
+		Number: &notion.NumberConfig{Format: notion.NumberConfigFormatDollar},
+	}
+	changed["Important"] = changed["Summary"]
+	delete(changed, "Views")
+	changedDB := srv.AddDatabase("", "Foo", changed)
+
+	cli, err := srv.Client()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	assert.NoError(t, foo.CheckCompatibility(ctx, cli, notion.Id(foo.DatabaseID)))
+	assert.NoError(t, foo.CheckCompatibility(ctx, cli, notion.Id(renamedDB.Id)))
+
+	err = foo.CheckCompatibility(ctx, cli, notion.Id(changedDB.Id))
+	incompatible := &database.IncompatibleError{}
+	require.ErrorAs(t, err, &incompatible)
+	assert.Equal(t, []string{
+		`number "Budget" has format dollar instead of euro`,
+		`property "Important" is rich_text instead of checkbox`,
+		`property "Views" is missing`,
+	}, incompatible.Problems)
+}
+
+func keys(m notion.PropertyValueMap) []string {
+	ks := []string{}
+	for k := range m {
+		ks = append(ks, k)
+	}
+
+	return ks
+}
@@ -0,0 +1,161 @@
+// Code generated by go-notion-codegen v0.0.2 from schema e600c08eb03cc960d86279de18bfeafa5a515c722d8bc851e538c2d84796247a with generator b20b9e0aa911b4941c2287355a392295620c76b002080214779e2d185414b799; DO NOT EDIT.
+
+package foo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/faetools/client"
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// Store holds foo entries.
+//
+// It is implemented by Repository, which uses Notion, and by Fake, which holds the entries in memory.
+type Store interface {
+	// Get returns the entry with the given ID.
+	Get(ctx context.Context, id ID) (Entry, error)
+	// Query returns an iterator over the entries that match the filter, in the order given by the sorts.
+	Query(filter *notion.Filter, sorts *notion.Sorts) *database.Iterator[Entry]
+	// Create creates an entry with the given property values, if they are valid.
+	Create(ctx context.Context, v PropertyValues) (Entry, error)
+	// Update saves the property values of the entry that were changed since its page was loaded,
+	// if they are valid.
+	Update(ctx context.Context, e Entry) (Entry, error)
+	// UpdateIfUnchanged is like Update, but returns a *database.ConflictError
+	// if the entry was edited after its page was loaded.
+	UpdateIfUnchanged(ctx context.Context, e Entry, opts ...database.UpdateOption) (Entry, error)
+	// Archive archives the entry with the given ID.
+	Archive(ctx context.Context, id ID) error
+}
+
+var (
+	_ Store = (*Repository)(nil)
+	_ Store = (*Fake)(nil)
+)
+
+// CheckCompatibility returns a *database.IncompatibleError if the foo database with the given ID
+// changed in a way the package can't handle since it was generated.
+func CheckCompatibility(ctx context.Context, cli *notion.Client, id notion.Id) error {
+	return database.CheckCompatibility(ctx, cli, id, Schema(), database.MatchByID(), database.StatusOptions(statusOptions))
+}
+
+// Repository gives access to the entries of a foo database.
+type Repository struct {
+	cli *notion.Client
+	id  notion.Id
+}
+
+// NewRepository returns a repository for the foo database with the given ID.
+//
+// Its client keeps to Notion's rate limit and retries requests that failed temporarily.
+func NewRepository(bearer string, id notion.Id, opts ...client.Option) (*Repository, error) {
+	cli, err := database.NewClient(bearer, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRepositoryWithClient(cli, id), nil
+}
+
+// NewRepositoryWithClient returns a repository for the foo database with the given ID
+// that uses the given client.
+func NewRepositoryWithClient(cli *notion.Client, id notion.Id) *Repository {
+	return &Repository{cli: cli, id: id}
+}
+
+// Client returns the client of the repository.
+func (r *Repository) Client() *notion.Client { return r.cli }
+
+// Get returns the entry with the given ID.
+func (r *Repository) Get(ctx context.Context, id ID) (Entry, error) {
+	return GetEntry(ctx, r.cli, id)
+}
+
+// Query returns an iterator over the entries that match the filter, in the order given by the sorts.
+func (r *Repository) Query(filter *notion.Filter, sorts *notion.Sorts) *database.Iterator[Entry] {
+	return Query(r.cli, r.id, filter, sorts)
+}
+
+// Expand returns a graph of the entries and the pages they relate to, up to the given depth.
+func (r *Repository) Expand(ctx context.Context, entries []Entry, depth int,
+	opts ...database.ExpandOption,
+) (*database.Graph, error) {
+	return Expand(ctx, r.cli, entries, depth, opts...)
+}
+
+// Create creates an entry with the given property values, if they are valid.
+func (r *Repository) Create(ctx context.Context, v PropertyValues) (Entry, error) {
+	if err := v.Validate(); err != nil {
+		return Entry{}, fmt.Errorf("creating foo entry: %w", err)
+	}
+
+	p, err := database.CreatePage(ctx, r.cli, r.id, v.ToPropertyValueMap(),
+		database.ExactNumbers(v.ToNumbers()), database.RawProperties(v.ToRawValues()),
+		database.PropertyIDs(propertyIDs))
+	if err != nil {
+		return Entry{}, fmt.Errorf("creating foo entry: %w", err)
+	}
+
+	return NewExactEntry(*p), nil
+}
+
+// Update saves the property values of the entry that were changed since its page was loaded,
+// if they are valid.
+// Other properties are left as they are, even if someone else changed them in the meantime.
+func (r *Repository) Update(ctx context.Context, e Entry) (Entry, error) {
+	changes := e.Changes()
+	if len(changes) == 0 {
+		return e, nil
+	}
+
+	if err := e.Validate(); err != nil {
+		return Entry{}, fmt.Errorf("updating foo entry %s: %w", e.ID, err)
+	}
+
+	p, err := database.UpdatePage(ctx, r.cli, notion.Id(e.ID), changes,
+		database.ExactNumbers(e.ToNumbers()), database.RawProperties(e.ToRawValues()),
+		database.PropertyIDs(propertyIDs))
+	if err != nil {
+		return Entry{}, fmt.Errorf("updating foo entry %s: %w", e.ID, err)
+	}
+
+	return NewExactEntry(*p), nil
+}
+
+// UpdateIfUnchanged is like Update, but returns a *database.ConflictError
+// if the entry was edited after its page was loaded.
+// With database.MergeChanges, it only does so if the same properties were changed.
+func (r *Repository) UpdateIfUnchanged(ctx context.Context, e Entry, opts ...database.UpdateOption) (Entry, error) {
+	changes := e.Changes()
+	if len(changes) == 0 {
+		return e, nil
+	}
+
+	if err := e.Validate(); err != nil {
+		return Entry{}, fmt.Errorf("updating foo entry %s: %w", e.ID, err)
+	}
+
+	// copy the options so that the caller's slice is not written to
+	opts = append(append([]database.UpdateOption{}, opts...),
+		database.ExactNumbers(e.ToNumbers()), database.RawProperties(e.ToRawValues()),
+		database.PropertyIDs(propertyIDs))
+
+	p, err := database.UpdatePageIfUnchanged(ctx, r.cli, e.exactPage(), changes, opts...)
+	if err != nil {
+		return Entry{}, fmt.Errorf("updating foo entry %s: %w", e.ID, err)
+	}
+
+	return NewExactEntry(*p), nil
+}
+
+// Archive archives the entry with the given ID.
+func (r *Repository) Archive(ctx context.Context, id ID) error {
+	if _, err := database.ArchivePage(ctx, r.cli, notion.Id(id)); err != nil {
+		return fmt.Errorf("archiving foo entry %s: %w", id, err)
+	}
+
+	return nil
+}
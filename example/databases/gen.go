@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 
+	"github.com/faetools/go-notion-codegen/database"
 	"github.com/faetools/go-notion-codegen/example/databases/bar"
 	"github.com/faetools/go-notion-codegen/example/databases/blub"
 	"github.com/faetools/go-notion-codegen/example/databases/foo"
@@ -13,16 +14,46 @@ import (
 
 //go:generate go run gen.go
 
+var zero float64
+
+// barProperties are the properties of bar databases with the rollups Notion adds to them.
+// Notion can't create rollups, so bar.Properties has none.
+func barProperties() notion.PropertyMetaMap {
+	props := notion.PropertyMetaMap{}
+
+	for name, meta := range bar.Properties {
+		props[name] = meta
+	}
+
+	props["Total Budget"] = notion.PropertyMeta{Type: notion.PropertyTypeRollup}
+	props["All Views"] = notion.PropertyMeta{Type: notion.PropertyTypeRollup}
+
+	return props
+}
+
 func main() {
 	fs := afero.NewOsFs()
 
-	for pkgName, props := range map[string]notion.PropertyMetaMap{
-		"bar":  bar.Properties,
-		"blub": blub.Properties,
-		"foo":  foo.Properties(true),
-	} {
-		if err := gen.PropertyValues(fs, pkgName, props); err != nil {
-			log.Fatal(err)
-		}
+	if err := gen.Databases(fs, "github.com/faetools/go-notion-codegen/example/databases",
+		gen.Database{PkgName: "bar", Properties: barProperties(), Constraints: map[string]gen.Constraint{
+			"Name":             {Required: true, Pattern: `^[A-Z][a-z]+( [a-z]+)*$`, MaxLength: 30},
+			"Description":      {MaxLength: 20},
+			"Number Of People": {Min: &zero},
+		}, Rollups: map[string]gen.Rollup{
+			"Total Budget": {Relation: "Related To", Property: "Budget", Function: "sum"},
+			"All Views":    {Relation: "Related To", Property: "Views", Function: "show_original"},
+		}},
+		gen.Database{PkgName: "blub", Properties: blub.Properties, Plain: true, PlainProperties: map[string]bool{
+			"Description": false,
+		}},
+		gen.Database{PkgName: "foo", ID: foo.DatabaseID, Properties: foo.Properties(true), NumberTypes: map[string]gen.NumberType{
+			"Views": gen.NumberInt64,
+		}, FormulaTypes: map[string]database.FormulaType{
+			"Score": database.FormulaNumber,
+		}, Statuses: map[string]database.StatusConfig{
+			"Stage": foo.Stage,
+		}, ByID: true},
+	); err != nil {
+		log.Fatal(err)
 	}
 }
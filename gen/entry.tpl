@@ -0,0 +1,147 @@
+package {{ .PkgName }}
+
+{{ template "imports" .Imports }}
+
+// ID is the ID of a {{ .PkgName }} entry.
+type ID notion.UUID
+
+// Entry is an entry of a {{ .PkgName }} database.
+type Entry struct {
+	ID   ID
+	Page notion.Page
+	// Numbers are the exact numbers of the page, if they are known.
+	Numbers database.Numbers
+	// Raw are the values of the page notion.Page can't hold, e.g. the results of formulas.
+	Raw database.RawValues
+	PropertyValues
+}
+
+// NewEntry returns the entry of the given page.
+func NewEntry(p notion.Page) Entry {
+	return NewExactEntry(database.Page{Page: p})
+}
+
+// NewExactEntry returns the entry of the given page, using its exact numbers and raw values.
+func NewExactEntry(p database.Page) Entry {
+	return Entry{
+		ID:             ID(p.Id),
+		Page:           p.Page,
+		Numbers:        p.Numbers,
+		Raw:            p.Raw,
+		PropertyValues: GetExactPropertyValues(p.Properties, p.Numbers, p.Raw),
+	}
+}
+
+// exactPage returns the page of the entry with its exact numbers and raw values.
+func (e Entry) exactPage() database.Page {
+	return database.Page{Page: e.Page, Numbers: e.Numbers, Raw: e.Raw}
+}
+
+// Changes returns the property values that were changed since the page of the entry was loaded.
+func (e Entry) Changes() notion.PropertyValueMap {
+	return Diff(GetExactPropertyValues(e.Page.Properties, e.Numbers, e.Raw), e.PropertyValues)
+}
+{{- if .WholeNumbers }}
+
+// Validate checks the property values like PropertyValues.Validate and that the whole numbers
+// read from the page had no fraction, which reading them cut off, unless they were changed since.
+func (e Entry) Validate() error {
+	val := &database.Validator{}
+	e.PropertyValues.validate(val)
+{{ if .ByID }}
+	_, nums, _ := database.ByID(propertyIDs, e.Page.Properties, e.Numbers, e.Raw)
+{{- else }}
+	nums := e.Numbers
+{{- end }}
+{{- range .WholeNumbers }}
+	val.Integral({{ printf "%q" .Key }}, nums[{{ printf "%q" .Key }}], {{ .WholeNumber }})
+{{- end }}
+
+	return val.Err()
+}
+{{- end }}
+{{- range .Files }}
+
+// Download{{ .Name }} downloads the files of {{ .Key | printf "%q" }} into the directory
+// and returns a manifest of them. Expired URLs are refreshed through the client.
+func (e Entry) Download{{ .Name }}(ctx context.Context, cli *notion.Client, fs afero.Fs, dir string,
+	opts ...database.DownloadOption,
+) (database.Manifest, error) {
+	return database.DownloadFiles(ctx, cli, fs, dir, notion.Id(e.ID), {{ .Key | printf "%q" }}, e.{{ .Name }}, opts...)
+}
+{{- end }}
+
+// GetEntry returns the entry with the given ID.
+func GetEntry(ctx context.Context, cli *notion.Client, id ID) (Entry, error) {
+	p, err := database.GetPage(ctx, cli, notion.Id(id))
+	if err != nil {
+		return Entry{}, fmt.Errorf("getting {{ .PkgName }} entry %s: %w", id, err)
+	}
+
+	return NewExactEntry(*p), nil
+}
+
+// Query returns an iterator over the entries of the database that match the filter,
+// in the order given by the sorts.
+func Query(cli *notion.Client, id notion.Id, filter *notion.Filter, sorts *notion.Sorts) *database.Iterator[Entry] {
+	return database.NewExactIterator(cli, id, filter, sorts, NewExactEntry)
+}
+
+// Expand returns a graph of the entries and the pages they relate to, up to the given depth.
+func Expand(ctx context.Context, cli *notion.Client, entries []Entry, depth int,
+	opts ...database.ExpandOption,
+) (*database.Graph, error) {
+	pages := make([]database.Page, len(entries))
+
+	for i, e := range entries {
+		pages[i] = e.exactPage()
+	}
+
+	return database.Expand(ctx, cli, pages, depth, opts...)
+}
+{{- range .Relations }}
+
+// {{ .Name }}Entries returns the entries {{ .Key | printf "%q" }} relates to.
+func (v PropertyValues) {{ .Name }}Entries(ctx context.Context, cli *notion.Client) ([]{{ .Target.Qualify "Entry" }}, error) {
+	entries := make([]{{ .Target.Qualify "Entry" }}, len(v.{{ .Name }}))
+
+	for i, id := range v.{{ .Name }} {
+		e, err := {{ .Target.Qualify "GetEntry" }}(ctx, cli, id)
+		if err != nil {
+			return nil, err
+		}
+
+		entries[i] = e
+	}
+
+	return entries, nil
+}
+
+// {{ .Name }}In returns the entries {{ .Key | printf "%q" }} relates to that are part of the graph.
+func (v PropertyValues) {{ .Name }}In(g *database.Graph) []{{ .Target.Qualify "Entry" }} {
+	return database.Related(g, v.{{ .Name }}, {{ .Target.Qualify "NewExactEntry" }})
+}
+{{- end }}
+{{- range .Users }}{{ if .IsUser }}
+
+// {{ .Name }}Name returns the name of the user of {{ .Key | printf "%q" }}, as the directory knows it.
+func (v PropertyValues) {{ .Name }}Name(ctx context.Context, dir *database.Directory) (string, error) {
+	return dir.Name(ctx, v.{{ .Name }})
+}
+
+// {{ .Name }}Email returns the email of the user of {{ .Key | printf "%q" }}, if the directory knows it.
+func (v PropertyValues) {{ .Name }}Email(ctx context.Context, dir *database.Directory) (string, error) {
+	return dir.Email(ctx, v.{{ .Name }})
+}
+{{- else }}
+
+// {{ .Name }}Names returns the names of the users of {{ .Key | printf "%q" }}, as the directory knows them.
+func (v PropertyValues) {{ .Name }}Names(ctx context.Context, dir *database.Directory) ([]string, error) {
+	return dir.Names(ctx, v.{{ .Name }})
+}
+
+// {{ .Name }}Emails returns the emails of the users of {{ .Key | printf "%q" }} the directory knows.
+func (v PropertyValues) {{ .Name }}Emails(ctx context.Context, dir *database.Directory) ([]string, error) {
+	return dir.Emails(ctx, v.{{ .Name }})
+}
+{{- end }}{{ end }}
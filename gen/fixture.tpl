@@ -0,0 +1,42 @@
+package {{ .PkgName }}
+
+{{ template "imports" .Imports }}
+
+// FixtureOption changes the property values of a fixture.
+type FixtureOption func(*PropertyValues)
+{{- range .Properties }}{{ if .Encoded }}
+
+// With{{ .Name }} sets {{ .Key | printf "%q" }} of a fixture.
+func With{{ .Name }}(v {{ .GoType }}) FixtureOption {
+	return func(f *PropertyValues) { f.{{ .Name }} = v }
+}
+{{- end }}{{ end }}
+
+// NewPropertyValuesFixture returns valid property values to be used in tests,
+// unless the schema requires relations or files, which have to be set with options.
+// The values are random, but the same on every call, and can be changed with options.
+func NewPropertyValuesFixture(opts ...FixtureOption) PropertyValues {
+	v := RandomPropertyValues(rand.New(rand.NewSource(1))) //nolint:gosec // fixtures do not need to be secure
+
+	for _, opt := range opts {
+		opt(&v)
+	}
+
+	return v
+}
+
+// NewPageFixture returns a page with the property values of a fixture.
+func NewPageFixture(opts ...FixtureOption) notion.Page {
+	return database.NewPage(NewPropertyValuesFixture(opts...).ToPropertyValueMap())
+}
+
+// RandomPropertyValues returns random property values.
+// Selects only use the options of the schema and whole numbers are generated for int properties.
+// The values fit the constraints, except that required relations and files stay empty.
+func RandomPropertyValues(r *rand.Rand, opts ...database.RandomOption) PropertyValues {
+	return PropertyValues{
+	{{- range .Properties }}{{ if .Random }}
+		{{ .Name }}: {{ .Random }},
+	{{- end }}{{ end }}
+	}
+}
@@ -1,9 +1,15 @@
 package gen
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"math"
+	"path"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -11,20 +17,223 @@ import (
 
 	"github.com/ettle/strcase"
 	"github.com/faetools/cgtools"
+	"github.com/faetools/go-notion-codegen/database"
 	"github.com/faetools/go-notion/pkg/notion"
 	"github.com/spf13/afero"
 )
 
+const (
+	importClient   = "github.com/faetools/client"
+	importNotion   = "github.com/faetools/go-notion/pkg/notion"
+	importDatabase = "github.com/faetools/go-notion-codegen/database"
+	importQuery    = "github.com/faetools/go-notion-codegen/query"
+	importAfero    = "github.com/spf13/afero"
+)
+
 var (
+	//go:embed imports.tpl
+	tplImportsRaw string
+
 	//go:embed property-values.tpl
 	tplPropertyValuesRaw string
 
-	tplPropertyValues = template.Must(template.New("property-values.tpl").Parse(tplPropertyValuesRaw))
+	//go:embed entry.tpl
+	tplEntryRaw string
+
+	//go:embed repository.tpl
+	tplRepositoryRaw string
+
+	//go:embed fake.tpl
+	tplFakeRaw string
+
+	//go:embed fixture.tpl
+	tplFixtureRaw string
+
+	tplPropertyValues = newTemplate("property-values.tpl", tplPropertyValuesRaw)
+	tplEntry          = newTemplate("entry.tpl", tplEntryRaw)
+	tplRepository     = newTemplate("repository.tpl", tplRepositoryRaw)
+	tplFake           = newTemplate("fake.tpl", tplFakeRaw)
+	tplFixture        = newTemplate("fixture.tpl", tplFixtureRaw)
 )
 
+// newTemplate returns a template that can make use of the imports template.
+func newTemplate(name, raw string) *template.Template {
+	return template.Must(template.Must(template.New(name).Parse(tplImportsRaw)).Parse(raw))
+}
+
+// Database describes a database for which code is generated.
+type Database struct {
+	// PkgName is the name of the generated package.
+	PkgName string
+	// ID is the ID of the database in Notion.
+	// It is optional, but relations to this database can only be typed if it is known.
+	ID notion.UUID
+	// Properties are the properties of the database.
+	Properties notion.PropertyMetaMap
+	// Constraints are checked by the generated Validate method, in addition to the schema.
+	// They are given by property name.
+	Constraints map[string]Constraint
+	// Plain makes titles, rich texts, selects, multi-selects, numbers and dates
+	// use plain Go types: string, []string, float64 and time.Time.
+	Plain bool
+	// PlainProperties overrides Plain for single properties, given by name.
+	PlainProperties map[string]bool
+	// Numbers is the type all numbers are generated as, decoded exactly from the numbers Notion sends.
+	// It takes precedence over Plain and the format of the numbers.
+	Numbers NumberType
+	// NumberTypes overrides Numbers for single properties, given by name.
+	NumberTypes map[string]NumberType
+	// FormulaTypes are the types of the results of formulas, given by property name.
+	// Notion doesn't tell them in the schema, so formulas without type are generated
+	// as database.Formula, which holds a result of any type.
+	FormulaTypes map[string]database.FormulaType
+	// Rollups tell what rollups roll up, given by property name.
+	// Notion doesn't tell it in the schema, so rollups without it are generated
+	// as database.Rollup, which holds a result of any type.
+	Rollups map[string]Rollup
+	// Statuses are the options and groups of status properties, given by property name.
+	// notion.PropertyMeta can't hold them, so statuses without them are generated as string.
+	Statuses map[string]database.StatusConfig
+	// ByID makes the generated code find property values by the IDs of their properties
+	// and address properties by ID when writing, so that renaming properties in Notion breaks nothing.
+	// Properties without ID in the schema are found by name.
+	ByID bool
+}
+
+// Rollup tells what a rollup property rolls up.
+type Rollup struct {
+	// Relation is the name of the relation property whose related pages are rolled up.
+	Relation string
+	// Property is the name of the property of the related pages that is rolled up.
+	Property string
+	// Function is the function of the rollup, e.g. sum or show_original.
+	Function string
+}
+
+// rollupResult returns the type of the result of the rollup function.
+func rollupResult(function string) (database.RollupType, bool) {
+	switch function {
+	case "count_all", "count", "count_values", "count_unique_values", "unique",
+		"count_empty", "empty", "count_not_empty", "not_empty",
+		"percent_empty", "percent_not_empty", "percent_checked", "percent_unchecked",
+		"sum", "average", "median", "min", "max", "range", "checked", "unchecked":
+		return database.RollupNumber, true
+	case "earliest_date", "latest_date", "date_range":
+		return database.RollupDate, true
+	case "show_original", "show_unique":
+		return database.RollupArray, true
+	default:
+		return "", false
+	}
+}
+
+// NumberType is a Go type numbers can be generated as.
+type NumberType string
+
+// The Go types numbers can be generated as.
+const (
+	// NumberDefault generates numbers as float32, like notion.PropertyValue holds them,
+	// or as int, money or percentage, depending on their format.
+	NumberDefault NumberType = ""
+	NumberFloat64 NumberType = "float64"
+	NumberInt64   NumberType = "int64"
+	// NumberDecimal generates numbers as database.Decimal.
+	NumberDecimal NumberType = "decimal"
+)
+
+func (t NumberType) check() error {
+	switch t {
+	case NumberDefault, NumberFloat64, NumberInt64, NumberDecimal:
+		return nil
+	default:
+		return fmt.Errorf("unknown number type %q", t)
+	}
+}
+
+// canBePlain reports whether properties of the type can use plain Go types.
+func canBePlain(typ notion.PropertyType) bool {
+	switch typ {
+	case notion.PropertyTypeTitle,
+		notion.PropertyTypeRichText,
+		notion.PropertyTypeSelect,
+		notion.PropertyTypeMultiSelect,
+		notion.PropertyTypeNumber,
+		notion.PropertyTypeDate:
+		return true
+	default:
+		return false
+	}
+}
+
+// Constraint declares what values of a property are valid.
+type Constraint struct {
+	// Required means the property must be set. A required checkbox must be checked.
+	Required bool
+	// Min and Max are the bounds of a number.
+	Min, Max *float64
+	// Pattern is a regular expression non-empty text must match.
+	Pattern string
+	// MaxLength is the maximum number of characters of text.
+	MaxLength int
+}
+
+// check checks that the constraint can be applied to the property.
+func (c Constraint) check(meta notion.PropertyMeta) error {
+	isText := meta.Type == notion.PropertyTypeTitle || meta.Type == notion.PropertyTypeRichText
+
+	switch {
+	case c.Required && meta.Type == notion.PropertyTypeNumber:
+		return fmt.Errorf("numbers are always set, so they can't be required")
+	case c.Required && (property{meta: meta}).isSet() == "":
+		return fmt.Errorf("%s properties can't be set, so they can't be required", meta.Type)
+	case (c.Min != nil || c.Max != nil) && meta.Type != notion.PropertyTypeNumber:
+		return fmt.Errorf("only numbers can have a minimum or maximum")
+	case (c.Pattern != "" || c.MaxLength > 0) && !isText:
+		return fmt.Errorf("only text can have a pattern or maximum length")
+	}
+
+	if _, err := regexp.Compile(c.Pattern); err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	return nil
+}
+
 type property struct {
-	Key  string
-	meta notion.PropertyMeta
+	Key        string
+	meta       notion.PropertyMeta
+	constraint Constraint
+	// plain means the property uses a plain Go type.
+	plain bool
+	// number is the type of a number that is decoded exactly, if any.
+	number NumberType
+	// formula is the type of the result of a formula, if it is known.
+	formula database.FormulaType
+	// rollup is what a rollup rolls up, if it is known.
+	rollup *Rollup
+	// rolledUp is the type of the property a rollup rolls up, if it is known.
+	rolledUp notion.PropertyType
+	// status holds the options and groups of a status, if they are known.
+	status *database.StatusConfig
+
+	// target is the generated package this property relates to, if any.
+	target *target
+}
+
+// target is a generated package that is the target of a relation.
+type target struct {
+	// PkgName is empty if the relation points to the database itself.
+	PkgName    string
+	ImportPath string
+}
+
+// Qualify returns the identifier as seen from the package relating to the target.
+func (t target) Qualify(ident string) string {
+	if t.PkgName == "" {
+		return ident
+	}
+
+	return t.PkgName + "." + ident
 }
 
 func (p property) Name() string {
@@ -32,6 +241,17 @@ func (p property) Name() string {
 }
 
 func (p property) GoType() string {
+	switch p.number {
+	case NumberFloat64, NumberInt64:
+		return string(p.number)
+	case NumberDecimal:
+		return "database.Decimal"
+	}
+
+	if p.plain {
+		return p.plainType()
+	}
+
 	switch p.meta.Type {
 	case notion.PropertyTypeTitle,
 		notion.PropertyTypeRichText:
@@ -42,55 +262,1291 @@ func (p property) GoType() string {
 		return "bool"
 	case notion.PropertyTypeMultiSelect:
 		return "notion.PropertyOptions"
+	case notion.PropertyTypeDate:
+		return "database.DateValue"
+	case notion.PropertyTypeFormula:
+		return p.formulaType()
+	case notion.PropertyTypeRollup:
+		return p.rollupType()
+	case database.PropertyTypeStatus:
+		if p.status != nil {
+			return p.Name() + "Option"
+		}
+
+		return "string"
+	case notion.PropertyTypePeople:
+		return "[]notion.User"
+	case notion.PropertyTypeCreatedBy,
+		notion.PropertyTypeLastEditedBy:
+		return "notion.User"
 	case notion.PropertyTypeNumber:
-		if p.meta.Number.Format == notion.NumberConfigFormatNumber {
+		switch {
+		case p.IsInt():
 			return "int"
+		case p.Currency() != "":
+			return "database.Money"
+		case p.IsPercent():
+			return "database.Percent"
+		default:
+			return "float32"
 		}
-
-		return "float32"
 	case notion.PropertyTypeRelation:
+		if p.target != nil {
+			return "[]" + p.target.Qualify("ID")
+		}
+
 		return "notion.References"
 	default:
 		return fmt.Sprintf("notion.%s", strcase.ToPascal(string(p.meta.Type)))
 	}
 }
 
+// formulaType returns the Go type of the result of the formula.
+func (p property) formulaType() string {
+	switch p.formula {
+	case database.FormulaString:
+		return "string"
+	case database.FormulaNumber:
+		return "float64"
+	case database.FormulaBoolean:
+		return "bool"
+	case database.FormulaDate:
+		return "database.DateValue"
+	default:
+		return "database.Formula"
+	}
+}
+
+// rollupType returns the Go type of the result of the rollup.
+func (p property) rollupType() string {
+	switch p.rollupResult() {
+	case database.RollupNumber:
+		return "float64"
+	case database.RollupDate:
+		return "database.DateValue"
+	case database.RollupArray:
+		switch p.rolledUp {
+		case notion.PropertyTypeNumber:
+			return "[]float64"
+		case notion.PropertyTypeDate:
+			return "[]database.DateValue"
+		default:
+			return "[]notion.PropertyValue"
+		}
+	default:
+		return "database.Rollup"
+	}
+}
+
+// rollupResult returns the type of the result of the rollup or nothing if it is not known.
+// The values a rollup shows are only known if the rolled up property is known.
+func (p property) rollupResult() database.RollupType {
+	if p.rollup == nil {
+		return ""
+	}
+
+	t, _ := rollupResult(p.rollup.Function)
+	if t == database.RollupArray && p.rolledUp == "" {
+		return ""
+	}
+
+	return t
+}
+
+// plainType returns the plain Go type of the property.
+func (p property) plainType() string {
+	switch p.meta.Type {
+	case notion.PropertyTypeMultiSelect:
+		return "[]string"
+	case notion.PropertyTypeNumber:
+		if p.IsInt() {
+			return "int"
+		}
+
+		return "float64"
+	case notion.PropertyTypeDate:
+		return "time.Time"
+	default:
+		return "string"
+	}
+}
+
 func (p property) IsInt() bool {
 	num := p.meta.Number
 	return num != nil && num.Format == notion.NumberConfigFormatNumber
 }
 
+// Currency returns the currency of the property if it holds amounts of money.
+func (p property) Currency() database.Currency {
+	if p.meta.Number == nil {
+		return ""
+	}
+
+	c, _ := database.CurrencyOf(p.meta.Number.Format)
+
+	return c
+}
+
+func (p property) IsPercent() bool {
+	num := p.meta.Number
+	return num != nil && num.Format == notion.NumberConfigFormatPercent
+}
+
 func (p property) GetFunc() string {
 	return fmt.Sprintf("Get%s()", strcase.ToPascal(string(p.meta.Type)))
 }
 
+// Getter returns the expression that gets the value of the property from a property value map.
+func (p property) Getter() string {
+	get := fmt.Sprintf("props[%q].%s", p.Key, p.GetFunc())
+
+	switch p.number {
+	case NumberFloat64:
+		return fmt.Sprintf("database.Float64(props[%[1]q], nums[%[1]q])", p.Key)
+	case NumberInt64:
+		return fmt.Sprintf("database.Int64(props[%[1]q], nums[%[1]q])", p.Key)
+	case NumberDecimal:
+		return fmt.Sprintf("database.DecimalOf(props[%[1]q], nums[%[1]q])", p.Key)
+	}
+
+	switch {
+	case p.IsInt():
+		return fmt.Sprintf("int(database.Int64(props[%[1]q], nums[%[1]q]))", p.Key)
+	case p.plain && p.meta.Type == notion.PropertyTypeNumber:
+		return fmt.Sprintf("database.Float64(props[%[1]q], nums[%[1]q])", p.Key)
+	case p.plain:
+		return p.plainGetter(get)
+	case p.Currency() != "":
+		return fmt.Sprintf("database.MoneyOf(props[%[1]q], nums[%[1]q], %[2]q)", p.Key, p.Currency())
+	case p.IsPercent():
+		return fmt.Sprintf("database.Percent(database.Float64(props[%[1]q], nums[%[1]q]))", p.Key)
+	case p.target != nil:
+		return fmt.Sprintf("database.IDs[%s](%s)", p.target.Qualify("ID"), get)
+	case p.meta.Type == notion.PropertyTypeDate:
+		return fmt.Sprintf("database.NewDateValue(%s)", get)
+	case p.meta.Type == notion.PropertyTypeFormula:
+		return p.formulaGetter()
+	case p.meta.Type == notion.PropertyTypeRollup:
+		return p.rollupGetter()
+	case p.meta.Type == database.PropertyTypeStatus:
+		if p.status != nil {
+			return fmt.Sprintf("%sOption(database.NewStatus(raw[%q]).Name)", p.Name(), p.Key)
+		}
+
+		return fmt.Sprintf("database.NewStatus(raw[%q]).Name", p.Key)
+	case p.meta.Type == notion.PropertyTypePeople:
+		return fmt.Sprintf("database.NewUsers(raw[%q])", p.Key)
+	case p.IsUser():
+		return fmt.Sprintf("database.NewUser(raw[%q])", p.Key)
+	default:
+		return get
+	}
+}
+
+// ExactNumber returns the expression of the exact number of the property, if it has one.
+func (p property) ExactNumber() string {
+	field := "v." + p.Name()
+
+	switch p.number {
+	case NumberFloat64:
+		return fmt.Sprintf("database.Float64Number(%s)", field)
+	case NumberInt64:
+		return fmt.Sprintf("database.Int64Number(%s)", field)
+	case NumberDecimal:
+		return field + ".Number()"
+	}
+
+	switch {
+	case p.meta.Type != notion.PropertyTypeNumber:
+		return ""
+	case p.IsInt():
+		return fmt.Sprintf("database.Int64Number(int64(%s))", field)
+	case p.plain:
+		return fmt.Sprintf("database.Float64Number(%s)", field)
+	case p.Currency() != "":
+		return field + ".Number()"
+	case p.IsPercent():
+		return fmt.Sprintf("database.Float64Number(float64(%s))", field)
+	default:
+		return ""
+	}
+}
+
+// WholeNumber returns the expression of the whole number of the entry's property as int64, if it holds one.
+func (p property) WholeNumber() string {
+	switch {
+	case p.number == NumberInt64:
+		return "e." + p.Name()
+	case p.number == NumberDefault && p.IsInt():
+		return fmt.Sprintf("int64(e.%s)", p.Name())
+	default:
+		return ""
+	}
+}
+
+// formulaGetter returns the expression that decodes the result of the formula.
+func (p property) formulaGetter() string {
+	get := fmt.Sprintf("database.NewFormula(raw[%q])", p.Key)
+
+	switch p.formula {
+	case database.FormulaString:
+		return get + ".String"
+	case database.FormulaNumber:
+		return get + ".Number"
+	case database.FormulaBoolean:
+		return get + ".Boolean"
+	case database.FormulaDate:
+		return get + ".Date"
+	default:
+		return get
+	}
+}
+
+// rollupGetter returns the expression that decodes the result of the rollup.
+func (p property) rollupGetter() string {
+	get := fmt.Sprintf("database.NewRollup(raw[%q])", p.Key)
+
+	switch p.rollupType() {
+	case "float64":
+		return get + ".Number"
+	case "database.DateValue":
+		return get + ".Date"
+	case "[]float64":
+		return get + ".Numbers()"
+	case "[]database.DateValue":
+		return get + ".Dates()"
+	case "[]notion.PropertyValue":
+		return get + ".Values()"
+	default:
+		return get
+	}
+}
+
+// plainGetter returns the expression that converts the value to the plain Go type.
+func (p property) plainGetter(get string) string {
+	switch p.meta.Type {
+	case notion.PropertyTypeSelect:
+		return get + ".Name"
+	case notion.PropertyTypeMultiSelect:
+		return get + ".GetNames()"
+	case notion.PropertyTypeNumber:
+		return fmt.Sprintf("float64(%s)", get)
+	case notion.PropertyTypeDate:
+		return get + ".Start"
+	default:
+		return get + ".Content()"
+	}
+}
+
+// Encoded returns the fields of the property value that holds the value of the property,
+// or nothing if the value can't be set.
+func (p property) Encoded() string {
+	field := "v." + p.Name()
+
+	var value string
+
+	switch p.meta.Type {
+	case notion.PropertyTypeTitle:
+		if p.plain {
+			value = fmt.Sprintf("Title: database.Text(%s)", field)
+			break
+		}
+
+		value = "Title: &" + field
+	case notion.PropertyTypeRichText:
+		if p.plain {
+			value = fmt.Sprintf("RichText: database.Text(%s)", field)
+			break
+		}
+
+		value = "RichText: &" + field
+	case notion.PropertyTypeSelect:
+		if p.plain {
+			value = fmt.Sprintf("Select: database.SelectName(%s)", field)
+			break
+		}
+
+		value = fmt.Sprintf("Select: database.Select(%s)", field)
+	case notion.PropertyTypeCheckbox:
+		value = "Checkbox: &" + field
+	case notion.PropertyTypeMultiSelect:
+		if p.plain {
+			value = fmt.Sprintf("MultiSelect: database.Options(%s)", field)
+			break
+		}
+
+		value = "MultiSelect: &" + field
+	case notion.PropertyTypeNumber:
+		if p.number == NumberDecimal {
+			value = fmt.Sprintf("Number: database.Number(%s.Float64())", field)
+			break
+		}
+
+		if p.number == NumberDefault && !p.plain && p.Currency() != "" {
+			value = fmt.Sprintf("Number: database.Number(%s.Float())", field)
+			break
+		}
+
+		value = fmt.Sprintf("Number: database.Number(%s)", field)
+	case notion.PropertyTypeRelation:
+		if p.target == nil {
+			value = "Relation: &" + field
+			break
+		}
+
+		value = fmt.Sprintf("Relation: database.References(%s)", field)
+	case notion.PropertyTypeDate:
+		if p.plain {
+			value = fmt.Sprintf("Date: database.Time(%s)", field)
+			break
+		}
+
+		value = fmt.Sprintf("Date: %s.Date()", field)
+	case notion.PropertyTypeFiles:
+		value = "Files: &" + field
+	case database.PropertyTypeStatus:
+		// the status itself is one of the raw values
+	default:
+		return ""
+	}
+
+	typ := "notion.PropertyType" + strcase.ToPascal(string(p.meta.Type))
+	if p.meta.Type == database.PropertyTypeStatus {
+		typ = "database.PropertyTypeStatus"
+	}
+
+	if value == "" {
+		value = "Type: " + typ
+	} else {
+		value = fmt.Sprintf("Type: %s, %s", typ, value)
+	}
+
+	if p.meta.Id == "" {
+		return value
+	}
+
+	return fmt.Sprintf("Id: %q, %s", p.meta.Id, value)
+}
+
+// RawValue returns the expression of the raw value of the property,
+// if it can be set and notion.PropertyValue can't hold it.
+func (p property) RawValue() string {
+	if p.meta.Type != database.PropertyTypeStatus {
+		return ""
+	}
+
+	if p.status != nil {
+		return fmt.Sprintf("database.Status(string(v.%s))", p.Name())
+	}
+
+	return fmt.Sprintf("database.Status(v.%s)", p.Name())
+}
+
+// KnownStatus reports whether the property is a status whose options are known.
+func (p property) KnownStatus() bool { return p.status != nil }
+
+// StatusOptions returns the options of the status with the names of their constants.
+func (p property) StatusOptions() []statusConst {
+	if p.status == nil {
+		return nil
+	}
+
+	consts := make([]statusConst, len(p.status.Options))
+
+	for i, opt := range p.status.Options {
+		consts[i] = statusConst{
+			Const: p.Name() + strcase.ToPascal(opt.Name),
+			Value: opt.Name,
+			Group: p.status.Group(opt.Name),
+		}
+	}
+
+	return consts
+}
+
+// StatusGroups returns the groups of the status with the names of their constants.
+func (p property) StatusGroups() []statusConst {
+	if p.status == nil {
+		return nil
+	}
+
+	consts := make([]statusConst, len(p.status.Groups))
+
+	for i, g := range p.status.Groups {
+		consts[i] = statusConst{Const: p.GroupConst(g.Name), Value: g.Name}
+	}
+
+	return consts
+}
+
+// GroupConst returns the name of the constant of the status group.
+func (p property) GroupConst(group string) string {
+	return p.Name() + "Group" + strcase.ToPascal(group)
+}
+
+// statusConst is a constant of an option or a group of a status.
+type statusConst struct {
+	Const, Value string
+	// Group is the group of an option, if it has one.
+	Group string
+}
+
+// Random returns the expression that generates a random value of the property,
+// or nothing if the zero value is used.
+func (p property) Random() string {
+	random := p.random()
+	if !p.plain || random == "" || p.IsInt() || p.number != NumberDefault {
+		return random
+	}
+
+	if p.meta.Type == notion.PropertyTypeDate {
+		return random + ".Start"
+	}
+
+	return p.plainGetter(random)
+}
+
+func (p property) random() string {
+	c := p.constraint
+
+	switch p.meta.Type {
+	case notion.PropertyTypeTitle,
+		notion.PropertyTypeRichText:
+		switch {
+		case c.Pattern != "":
+			return fmt.Sprintf("database.RandomMatch(r, %s, %d)", p.PatternVar(), c.MaxLength)
+		case c.MaxLength > 0:
+			return fmt.Sprintf("database.Truncate(database.RandomText(r, opts...), %d)", c.MaxLength)
+		default:
+			return "database.RandomText(r, opts...)"
+		}
+	case notion.PropertyTypeSelect:
+		return fmt.Sprintf("database.RandomSelect(%s)", strings.Join(optionNames(p.meta.Select), ", "))
+	case notion.PropertyTypeMultiSelect:
+		if c.Required {
+			return fmt.Sprintf("database.RandomNonEmptyMultiSelect(%s)", strings.Join(optionNames(p.meta.MultiSelect), ", "))
+		}
+
+		return fmt.Sprintf("database.RandomMultiSelect(%s)", strings.Join(optionNames(p.meta.MultiSelect), ", "))
+	case notion.PropertyTypeCheckbox:
+		if c.Required {
+			return "true"
+		}
+
+		return "r.Intn(2) == 1"
+	case notion.PropertyTypeNumber:
+		if c.Min != nil || c.Max != nil {
+			return p.randomBetween()
+		}
+
+		switch {
+		case p.number == NumberFloat64:
+			return "float64(r.Intn(100_000)) / 100"
+		case p.number == NumberInt64:
+			return "r.Int63n(1000)"
+		case p.number == NumberDecimal:
+			return "database.RandomDecimal(r)"
+		case p.IsInt():
+			return "r.Intn(1000)"
+		case p.plain:
+			return "database.RandomNumber(r)"
+		case p.Currency() != "":
+			return fmt.Sprintf("database.RandomMoney(r, %q)", p.Currency())
+		case p.IsPercent():
+			return "database.RandomPercent(r)"
+		default:
+			return "database.RandomNumber(r)"
+		}
+	case notion.PropertyTypeDate:
+		return "database.RandomDate(r, opts...)"
+	case database.PropertyTypeStatus:
+		if p.status == nil {
+			return ""
+		}
+
+		args := []string{"r"}
+		for _, opt := range p.status.Options {
+			args = append(args, fmt.Sprintf("%q", opt.Name))
+		}
+
+		return fmt.Sprintf("%sOption(database.RandomSelect(%s).Name)", p.Name(), strings.Join(args, ", "))
+	case notion.PropertyTypeRelation:
+		if p.target == nil {
+			return ""
+		}
+
+		// relating to random pages would not be valid
+		return p.GoType() + "{}"
+	default:
+		return ""
+	}
+}
+
+// randomBetween returns the expression of a random number within the minimum and maximum of the property.
+// Without one of them, the range of the other random numbers is moved to include the other.
+func (p property) randomBetween() string {
+	lo, hi := 0.0, 1000.0
+	if p.IsPercent() && !p.plain && p.number == NumberDefault {
+		hi = 1
+	}
+
+	width := hi - lo
+
+	switch c := p.constraint; {
+	case c.Min != nil && c.Max != nil:
+		lo, hi = *c.Min, *c.Max
+	case c.Min != nil:
+		lo, hi = *c.Min, math.Max(hi, *c.Min+width)
+	case c.Max != nil:
+		lo, hi = math.Min(lo, *c.Max-width), *c.Max
+	}
+
+	between := fmt.Sprintf("database.RandomBetween(r, %v, %v, %%d)", lo, hi)
+
+	switch {
+	case p.number == NumberFloat64:
+		return fmt.Sprintf(between, 2)
+	case p.number == NumberInt64:
+		return fmt.Sprintf("int64(%s)", fmt.Sprintf(between, 0))
+	case p.number == NumberDecimal:
+		return fmt.Sprintf("database.Decimal(database.Float64Number(%s))", fmt.Sprintf(between, 2))
+	case p.IsInt():
+		return fmt.Sprintf("int(%s)", fmt.Sprintf(between, 0))
+	case p.plain:
+	case p.Currency() != "":
+		return fmt.Sprintf("database.NewMoney(%s, %q)", fmt.Sprintf(between, 0), p.Currency())
+	case p.IsPercent():
+		return fmt.Sprintf("database.Percent(%s)", fmt.Sprintf(between, 2))
+	}
+
+	return fmt.Sprintf("database.RandomNumberBetween(r, %v, %v)", lo, hi)
+}
+
+// optionNames returns the arguments to choose one of the options randomly.
+func optionNames(opts *notion.PropertyOptionsWrapper) []string {
+	args := []string{"r"}
+
+	if opts == nil {
+		return args
+	}
+
+	for _, opt := range opts.Options {
+		args = append(args, fmt.Sprintf("%q", opt.Name))
+	}
+
+	return args
+}
+
+// isSet returns the expression that reports whether the property is set,
+// or nothing if the value can't be set.
+func (p property) isSet() string {
+	field := "v." + p.Name()
+
+	if p.plain {
+		switch p.meta.Type {
+		case notion.PropertyTypeMultiSelect:
+			return fmt.Sprintf("len(%s) > 0", field)
+		case notion.PropertyTypeDate:
+			return fmt.Sprintf("!%s.IsZero()", field)
+		case notion.PropertyTypeNumber:
+			return ""
+		default:
+			return field + ` != ""`
+		}
+	}
+
+	switch p.meta.Type {
+	case notion.PropertyTypeTitle,
+		notion.PropertyTypeRichText:
+		return field + `.Content() != ""`
+	case notion.PropertyTypeSelect:
+		return fmt.Sprintf(`%[1]s.Name != "" || %[1]s.Id != ""`, field)
+	case notion.PropertyTypeCheckbox:
+		return field
+	case notion.PropertyTypeMultiSelect,
+		notion.PropertyTypeRelation,
+		notion.PropertyTypeFiles:
+		return fmt.Sprintf("len(%s) > 0", field)
+	case notion.PropertyTypeDate:
+		return fmt.Sprintf("!%s.IsZero()", field)
+	case database.PropertyTypeStatus:
+		return field + ` != ""`
+	default:
+		return ""
+	}
+}
+
+// Checks returns the statements that validate the value of the property.
+func (p property) Checks() []string {
+	field := "v." + p.Name()
+	checks := []string{}
+	c := p.constraint
+
+	if c.Required {
+		checks = append(checks, fmt.Sprintf("val.Required(%q, %s)", p.Key, p.isSet()))
+	}
+
+	text, name, check, num := field+".Content()", field+".Name", "val.Options", fmt.Sprintf("float64(%s)", field)
+	if p.plain {
+		text, name, check = field, field, "val.Names"
+	}
+
+	switch p.meta.Type {
+	case notion.PropertyTypeSelect:
+		if names := optionNames(p.meta.Select); len(names) > 1 {
+			args := append([]string{fmt.Sprintf("%q", p.Key), name}, names[1:]...)
+			checks = append(checks, fmt.Sprintf("val.Option(%s)", strings.Join(args, ", ")))
+		}
+	case database.PropertyTypeStatus:
+		if p.status != nil && len(p.status.Options) > 0 {
+			args := []string{fmt.Sprintf("%q", p.Key), fmt.Sprintf("string(%s)", field)}
+			for _, opt := range p.status.Options {
+				args = append(args, fmt.Sprintf("%q", opt.Name))
+			}
+
+			checks = append(checks, fmt.Sprintf("val.Option(%s)", strings.Join(args, ", ")))
+		}
+	case notion.PropertyTypeMultiSelect:
+		if names := optionNames(p.meta.MultiSelect); len(names) > 1 {
+			args := append([]string{fmt.Sprintf("%q", p.Key), field}, names[1:]...)
+			checks = append(checks, fmt.Sprintf("%s(%s)", check, strings.Join(args, ", ")))
+		}
+	case notion.PropertyTypeNumber:
+		switch {
+		case p.number == NumberDecimal:
+			checks = append(checks, fmt.Sprintf("val.Decimal(%q, %s)", p.Key, field))
+			num = field + ".Float64()"
+		case p.number != NumberDefault:
+		case p.IsInt():
+			checks = append(checks, fmt.Sprintf("val.Int(%q, %s)", p.Key, field))
+		}
+
+		if cur := p.Currency(); cur != "" && !p.plain && p.number == NumberDefault {
+			checks = append(checks, fmt.Sprintf("val.Currency(%q, %s, %q)", p.Key, field, cur))
+			num = field + ".Float()"
+		}
+
+		if c.Min != nil {
+			checks = append(checks, fmt.Sprintf("val.Min(%q, %s, %v)", p.Key, num, *c.Min))
+		}
+
+		if c.Max != nil {
+			checks = append(checks, fmt.Sprintf("val.Max(%q, %s, %v)", p.Key, num, *c.Max))
+		}
+	case notion.PropertyTypeDate:
+		// plain dates are no ranges
+		if !p.plain {
+			checks = append(checks, fmt.Sprintf("val.DateRange(%q, %s)", p.Key, field))
+		}
+	case notion.PropertyTypeTitle, notion.PropertyTypeRichText:
+		if c.Pattern != "" {
+			checks = append(checks, fmt.Sprintf("val.Pattern(%q, %s, %s)", p.Key, text, p.PatternVar()))
+		}
+
+		if c.MaxLength > 0 {
+			checks = append(checks, fmt.Sprintf("val.MaxLength(%q, %s, %d)", p.Key, text, c.MaxLength))
+		}
+	}
+
+	return checks
+}
+
+// IsUser reports whether the property holds a single user.
+func (p property) IsUser() bool {
+	return p.meta.Type == notion.PropertyTypeCreatedBy || p.meta.Type == notion.PropertyTypeLastEditedBy
+}
+
+// Const returns the name of the constant holding the name of the property.
+func (p property) Const() string { return "Prop" + p.Name() }
+
+// ID returns the ID of the property, if it is known.
+func (p property) ID() string { return p.meta.Id }
+
+// Pattern returns the pattern non-empty text of the property must match, if any.
+func (p property) Pattern() string { return p.constraint.Pattern }
+
+// PatternVar returns the name of the variable holding the compiled pattern.
+func (p property) PatternVar() string { return strcase.ToCamel(p.Key) + "Pattern" }
+
+// Target returns the generated package this property relates to.
+func (p property) Target() target { return *p.target }
+
+// pkg holds everything we know about a package we generate.
+type pkg struct {
+	Database
+	props []property
+}
+
+// schema returns a Go literal of the properties of the database, encoded as JSON.
+func (p pkg) schema() (string, error) {
+	b, err := json.MarshalIndent(p.Properties, "", "\t")
+	if err != nil {
+		return "", fmt.Errorf("encoding schema of %s: %w", p.PkgName, err)
+	}
+
+	if strings.Contains(string(b), "`") {
+		return strconv.Quote(string(b)), nil
+	}
+
+	return "`" + string(b) + "`", nil
+}
+
+// imports returns the import paths of all generated packages this package relates to,
+// preceded by the given import paths.
+func (p pkg) imports(imports ...string) []string {
+	seen := map[string]bool{}
+
+	for _, prop := range p.props {
+		if prop.target == nil || prop.target.ImportPath == "" || seen[prop.target.ImportPath] {
+			continue
+		}
+
+		seen[prop.target.ImportPath] = true
+		imports = append(imports, prop.target.ImportPath)
+	}
+
+	return imports
+}
+
+// files returns all properties that hold files.
+func (p pkg) files() []property {
+	files := []property{}
+
+	for _, prop := range p.props {
+		if prop.meta.Type == notion.PropertyTypeFiles {
+			files = append(files, prop)
+		}
+	}
+
+	return files
+}
+
+// wholeNumbers returns all properties that hold whole numbers.
+func (p pkg) wholeNumbers() []property {
+	props := []property{}
+
+	for _, prop := range p.props {
+		if prop.WholeNumber() != "" {
+			props = append(props, prop)
+		}
+	}
+
+	return props
+}
+
+// users returns all properties that hold users.
+func (p pkg) users() []property {
+	users := []property{}
+
+	for _, prop := range p.props {
+		if prop.meta.Type == notion.PropertyTypePeople || prop.IsUser() {
+			users = append(users, prop)
+		}
+	}
+
+	return users
+}
+
+// relations returns all properties that relate to a generated package.
+func (p pkg) relations() []property {
+	rels := []property{}
+
+	for _, prop := range p.props {
+		if prop.target != nil {
+			rels = append(rels, prop)
+		}
+	}
+
+	return rels
+}
+
 type ctxPropertyValues struct {
 	PkgName    string
+	Imports    []string
 	Properties []property
+	ByID       bool
+	// Schema is a Go literal of the properties of the database, encoded as JSON.
+	Schema string
+}
+
+// Statuses returns the statuses whose options are known.
+func (c ctxPropertyValues) Statuses() []property {
+	props := []property{}
+
+	for _, p := range c.Properties {
+		if p.KnownStatus() {
+			props = append(props, p)
+		}
+	}
+
+	return props
 }
 
-// PropertyValues generates the go file associated with the property values of a database.
+// PropertyIDs returns the properties whose ID is known.
+func (c ctxPropertyValues) PropertyIDs() []property {
+	props := []property{}
+
+	for _, p := range c.Properties {
+		if p.ID() != "" {
+			props = append(props, p)
+		}
+	}
+
+	return props
+}
+
+type ctxEntry struct {
+	PkgName   string
+	Imports   []string
+	Relations []property
+	Users     []property
+	Files     []property
+	// WholeNumbers are the properties holding whole numbers.
+	WholeNumbers []property
+	ByID         bool
+	// Statuses tells whether the options of any status are known.
+	Statuses bool
+}
+
+// PropertyValues generates the go files associated with the property values of a database.
 func PropertyValues(fs afero.Fs, pkgName string, m notion.PropertyMetaMap) error {
+	return Databases(fs, "", Database{PkgName: pkgName, Properties: m})
+}
+
+// Databases generates the go files of several databases at once.
+//
+// A relation to any of the databases is typed with the ID of the related database's package
+// and gets a method that returns the related entries.
+// Relations that would lead to an import cycle stay untyped.
+//
+// The import path is the path of the package containing the generated packages.
+//
+// Generated files start with a header that tells the version of the generator
+// and fingerprints of the schema and of the generator.
+// Packages whose files were generated by the same version and code from the same schema are skipped.
+// Files generated by a newer version are never overwritten.
+func Databases(fs afero.Fs, importPath string, dbs ...Database) error {
+	pkgs := make([]*pkg, len(dbs))
+	byID := map[string]*pkg{}
+
+	for i, db := range dbs {
+		props, err := getProperties(db)
+		if err != nil {
+			return fmt.Errorf("generating %s: %w", db.PkgName, err)
+		}
+
+		pkgs[i] = &pkg{Database: db, props: props}
+
+		if db.ID != "" {
+			byID[database.NormalizeID(db.ID)] = pkgs[i]
+		}
+	}
+
+	// we want every run to have the same result
+	sort.Slice(pkgs, func(i, j int) bool {
+		return pkgs[i].PkgName < pkgs[j].PkgName
+	})
+
+	if err := resolveRelations(importPath, pkgs, byID); err != nil {
+		return err
+	}
+
+	if err := resolveRollups(pkgs, byID); err != nil {
+		return err
+	}
+
 	g := cgtools.NewGenerator(fs)
 
-	props := make([]property, 0, len(m))
+	for _, p := range pkgs {
+		if err := generate(fs, g, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getProperties(db Database) ([]property, error) {
+	props := make([]property, 0, len(db.Properties))
+
+	for name := range db.Constraints {
+		if _, ok := db.Properties[name]; !ok {
+			return nil, fmt.Errorf("constraint for unknown property %q", name)
+		}
+	}
+
+	if err := db.Numbers.check(); err != nil {
+		return nil, err
+	}
+
+	for name, t := range db.NumberTypes {
+		meta, ok := db.Properties[name]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("number type for unknown property %q", name)
+		case meta.Type != notion.PropertyTypeNumber:
+			return nil, fmt.Errorf("%s property %q is no number", meta.Type, name)
+		}
+
+		if err := t.check(); err != nil {
+			return nil, fmt.Errorf("property %q: %w", name, err)
+		}
+	}
+
+	for name, t := range db.FormulaTypes {
+		meta, ok := db.Properties[name]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("formula type for unknown property %q", name)
+		case meta.Type != notion.PropertyTypeFormula:
+			return nil, fmt.Errorf("%s property %q is no formula", meta.Type, name)
+		}
+
+		switch t {
+		case database.FormulaString, database.FormulaNumber, database.FormulaBoolean, database.FormulaDate:
+		default:
+			return nil, fmt.Errorf("property %q: unknown formula type %q", name, t)
+		}
+	}
+
+	for name, r := range db.Rollups {
+		meta, ok := db.Properties[name]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("rollup for unknown property %q", name)
+		case meta.Type != notion.PropertyTypeRollup:
+			return nil, fmt.Errorf("%s property %q is no rollup", meta.Type, name)
+		}
+
+		if rel := db.Properties[r.Relation]; rel.Type != notion.PropertyTypeRelation {
+			return nil, fmt.Errorf("rollup %q: %q is no relation", name, r.Relation)
+		}
+
+		if _, ok := rollupResult(r.Function); !ok {
+			return nil, fmt.Errorf("rollup %q: unknown function %q", name, r.Function)
+		}
+	}
+
+	for name := range db.Statuses {
+		meta, ok := db.Properties[name]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("status options for unknown property %q", name)
+		case meta.Type != database.PropertyTypeStatus:
+			return nil, fmt.Errorf("%s property %q is no status", meta.Type, name)
+		}
+	}
+
+	for name, plain := range db.PlainProperties {
+		meta, ok := db.Properties[name]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("plain Go type for unknown property %q", name)
+		case plain && !canBePlain(meta.Type):
+			return nil, fmt.Errorf("%s property %q can't use a plain Go type", meta.Type, name)
+		}
+	}
+
+	for key, val := range db.Properties {
+		c := db.Constraints[key]
+		if err := c.check(val); err != nil {
+			return nil, fmt.Errorf("constraint for property %q: %w", key, err)
+		}
+
+		plain, ok := db.PlainProperties[key]
+		if !ok {
+			plain = db.Plain && canBePlain(val.Type)
+		}
+
+		number, ok := db.NumberTypes[key]
+		if !ok && val.Type == notion.PropertyTypeNumber {
+			number = db.Numbers
+		}
 
-	for key, val := range m {
 		props = append(props, property{
 			// we get only title keys from notion
-			Key:  strings.Title(key),
-			meta: val,
+			Key:        strings.Title(key),
+			meta:       val,
+			constraint: c,
+			plain:      plain,
+			number:     number,
+			formula:    db.FormulaTypes[key],
 		})
+
+		if r, ok := db.Rollups[key]; ok {
+			props[len(props)-1].rollup = &r
+		}
+
+		if c, ok := db.Statuses[key]; ok {
+			props[len(props)-1].status = &c
+		}
 	}
 
 	// we want every run to have the same result
 	sort.Slice(props, func(i, j int) bool {
+		if props[i].Name() != props[j].Name() {
+			return props[i].Name() < props[j].Name()
+		}
+
 		return props[i].Key < props[j].Key
 	})
 
-	return g.WriteTemplate(filepath.Join(pkgName, pkgName+".gen.go"),
-		tplPropertyValues, ctxPropertyValues{
-			PkgName:    pkgName,
-			Properties: props,
-		})
+	if err := checkIdentifiers(props); err != nil {
+		return nil, err
+	}
+
+	return props, nil
+}
+
+// reservedIdentifiers are the package-level identifiers every generated package declares.
+var reservedIdentifiers = []string{
+	"CheckCompatibility", "Diff", "Entry", "Expand", "Fake", "FixtureOption", "GetEntry",
+	"GetExactPropertyValues", "GetPropertyValues", "ID", "NewEntry", "NewExactEntry", "NewFake",
+	"NewPageFixture", "NewPropertyValuesFixture", "NewRepository", "NewRepositoryWithClient",
+	"PropertyValues", "Query", "RandomPropertyValues", "Repository", "Schema", "Store",
+}
+
+// checkIdentifiers returns an error if two properties, or the options and groups of statuses,
+// would be given Go identifiers that only differ in case or are declared by every generated package,
+// which would not compile or be easily mixed up.
+func checkIdentifiers(props []property) error {
+	type declaration struct{ ident, what string }
+
+	fields := map[string]declaration{}
+	idents := map[string]declaration{}
+
+	for _, ident := range reservedIdentifiers {
+		idents[strings.ToLower(ident)] = declaration{ident: ident, what: "the generated " + ident}
+	}
+
+	declare := func(declared map[string]declaration, ident, what string) error {
+		key := strings.ToLower(ident)
+
+		switch other, ok := declared[key]; {
+		case !ok:
+			declared[key] = declaration{ident: ident, what: what}
+			return nil
+		case other.ident == ident:
+			return fmt.Errorf("%s and %s would both be named %s", other.what, what, ident)
+		default:
+			return fmt.Errorf("%s and %s would be named %s and %s", other.what, what, other.ident, ident)
+		}
+	}
+
+	for _, p := range props {
+		what := fmt.Sprintf("property %q", p.Key)
+
+		if err := declare(fields, p.Name(), what); err != nil {
+			return err
+		}
+
+		consts := []string{p.Const(), "With" + p.Name()}
+		if p.ID() != "" {
+			consts = append(consts, p.Const()+"ID")
+		}
+
+		if p.Pattern() != "" {
+			consts = append(consts, p.PatternVar())
+		}
+
+		if p.KnownStatus() {
+			consts = append(consts, p.Name()+"Option")
+		}
+
+		if len(p.StatusGroups()) > 0 {
+			consts = append(consts, p.Name()+"Group")
+		}
+
+		for _, ident := range consts {
+			if err := declare(idents, ident, what); err != nil {
+				return err
+			}
+		}
+
+		for _, opt := range p.StatusOptions() {
+			if err := declare(idents, opt.Const, fmt.Sprintf("option %q of %s", opt.Value, what)); err != nil {
+				return err
+			}
+		}
+
+		for _, g := range p.StatusGroups() {
+			if err := declare(idents, g.Const, fmt.Sprintf("group %q of %s", g.Value, what)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveRelations sets the target of all relations to one of the packages.
+func resolveRelations(importPath string, pkgs []*pkg, byID map[string]*pkg) error {
+	// which package imports which
+	imports := map[*pkg]map[*pkg]bool{}
+
+	for _, p := range pkgs {
+		imports[p] = map[*pkg]bool{}
+	}
+
+	for _, p := range pkgs {
+		for i, prop := range p.props {
+			if prop.meta.Type != notion.PropertyTypeRelation || prop.meta.Relation == nil {
+				continue
+			}
+
+			to, ok := byID[database.NormalizeID(prop.meta.Relation.DatabaseId)]
+			switch {
+			case !ok:
+				continue
+			case to == p:
+				p.props[i].target = &target{}
+				continue
+			case reaches(imports, to, p):
+				// importing the package would lead to an import cycle
+				continue
+			case importPath == "":
+				return fmt.Errorf("relating %s to %s: no import path given", p.PkgName, to.PkgName)
+			}
+
+			imports[p][to] = true
+			p.props[i].target = &target{
+				PkgName:    to.PkgName,
+				ImportPath: path.Join(importPath, to.PkgName),
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveRollups sets the type of the property each rollup rolls up,
+// if the related database is one of the packages.
+func resolveRollups(pkgs []*pkg, byID map[string]*pkg) error {
+	for _, p := range pkgs {
+		for i, prop := range p.props {
+			if prop.rollup == nil {
+				continue
+			}
+
+			rel := p.Properties[prop.rollup.Relation]
+			if rel.Relation == nil {
+				continue
+			}
+
+			to, ok := byID[database.NormalizeID(rel.Relation.DatabaseId)]
+			if !ok {
+				continue
+			}
+
+			meta, ok := to.Properties[prop.rollup.Property]
+			if !ok {
+				return fmt.Errorf("generating %s: rollup %q: %s has no property %q",
+					p.PkgName, prop.Key, to.PkgName, prop.rollup.Property)
+			}
+
+			p.props[i].rolledUp = meta.Type
+		}
+	}
+
+	return nil
+}
+
+// reaches reports whether package from imports package to, directly or indirectly.
+func reaches(imports map[*pkg]map[*pkg]bool, from, to *pkg) bool {
+	if from == to {
+		return true
+	}
+
+	for p := range imports[from] {
+		if reaches(imports, p, to) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// file is a file generated for a package.
+type file struct {
+	path string
+	tpl  *template.Template
+	data interface{}
+}
+
+// generate generates the files of the package,
+// unless they were generated by the same version of the generator from the same schema.
+func generate(fs afero.Fs, g *cgtools.Generator, p *pkg) error {
+	h, err := p.header()
+	if err != nil {
+		return err
+	}
+
+	schema, err := p.schema()
+	if err != nil {
+		return err
+	}
+
+	files := []file{
+		{p.PkgName + ".gen.go", tplPropertyValues, ctxPropertyValues{
+			PkgName:    p.PkgName,
+			Imports:    p.imports("encoding/json", "regexp", "time", importDatabase, importNotion),
+			Properties: p.props,
+			ByID:       p.ByID,
+			Schema:     schema,
+		}},
+		{"entry.gen.go", tplEntry, ctxEntry{
+			PkgName:      p.PkgName,
+			Imports:      p.imports("context", "fmt", importAfero, importDatabase, importNotion),
+			Relations:    p.relations(),
+			Users:        p.users(),
+			Files:        p.files(),
+			WholeNumbers: p.wholeNumbers(),
+			ByID:         p.ByID,
+		}},
+		{"repository.gen.go", tplRepository, ctxEntry{
+			PkgName:  p.PkgName,
+			Imports:  []string{"context", "fmt", importClient, importDatabase, importNotion},
+			ByID:     p.ByID,
+			Statuses: len(p.Statuses) > 0,
+		}},
+		{"fake.gen.go", tplFake, ctxEntry{
+			PkgName:  p.PkgName,
+			Imports:  []string{"context", "fmt", "net/http", "sync", "time", importDatabase, importQuery, importNotion},
+			Statuses: len(p.Statuses) > 0,
+		}},
+		{"fixture.gen.go", tplFixture, ctxPropertyValues{
+			PkgName:    p.PkgName,
+			Imports:    p.imports("math/rand", "time", importDatabase, importNotion),
+			Properties: p.props,
+		}},
+	}
+
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = filepath.Join(p.PkgName, f.path)
+	}
+
+	same, err := upToDate(fs, h, paths)
+	if err != nil {
+		return fmt.Errorf("generating %s: %w", p.PkgName, err)
+	}
+
+	if same {
+		return nil
+	}
+
+	for i, f := range files {
+		b := &bytes.Buffer{}
+		fmt.Fprintf(b, "%s\n\n", h)
+
+		if err := f.tpl.Execute(b, f.data); err != nil {
+			return fmt.Errorf("executing template %s: %w", f.tpl.Name(), err)
+		}
+
+		if err := g.WriteBytes(paths[i], b.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -2,9 +2,13 @@ package gen_test
 
 import (
 	"os"
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/faetools/go-notion-codegen/database"
 	"github.com/faetools/go-notion-codegen/gen"
+	"github.com/faetools/go-notion-codegen/notioncodegen"
 	"github.com/faetools/go-notion/pkg/notion"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
@@ -73,11 +77,116 @@ func TestPropertyValues(t *testing.T) {
 
 	assert.Equal(t, `package mypackage
 
-import "github.com/faetools/go-notion/pkg/notion"
+import (
+	"encoding/json"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// The names of the properties in Notion.
+const (
+	PropCheck         = "Check"
+	PropMyDate        = "My Date"
+	PropMyFiles       = "My Files"
+	PropMyFloat       = "My Float"
+	PropMyMultiSelect = "My Multi Select"
+	PropMyNumber      = "My Number"
+	PropMyRelation    = "My Relation"
+	PropMyRichtext    = "My Richtext"
+	PropMySelect      = "My Select"
+	PropMyTitle       = "My Title"
+)
+
+// The IDs of the properties in Notion, which don't change when the properties are renamed.
+const (
+	PropMyTitleID = "title"
+)
+
+// schema holds the properties of the database the package was generated from.
+const schema = `+"`"+`{
+	"My Title": {
+		"id": "title",
+		"name": "",
+		"title": {},
+		"type": "title"
+	},
+	"check": {
+		"checkbox": {},
+		"id": "",
+		"name": "",
+		"type": "checkbox"
+	},
+	"my date": {
+		"date": {},
+		"id": "",
+		"name": "",
+		"type": "date"
+	},
+	"my files": {
+		"files": {},
+		"id": "",
+		"name": "",
+		"type": "files"
+	},
+	"my float": {
+		"id": "",
+		"name": "",
+		"number": {
+			"format": "number_with_commas"
+		},
+		"type": "number"
+	},
+	"my multi select": {
+		"id": "",
+		"multi_select": {
+			"options": []
+		},
+		"name": "",
+		"type": "multi_select"
+	},
+	"my number": {
+		"id": "",
+		"name": "",
+		"number": {
+			"format": "number"
+		},
+		"type": "number"
+	},
+	"my relation": {
+		"id": "",
+		"name": "",
+		"type": "relation"
+	},
+	"my richtext": {
+		"id": "",
+		"name": "",
+		"rich_text": {},
+		"type": "rich_text"
+	},
+	"my select": {
+		"id": "",
+		"name": "",
+		"select": {
+			"options": []
+		},
+		"type": "select"
+	}
+}`+"`"+`
+
+// Schema returns the properties of the database the package was generated from.
+func Schema() notion.PropertyMetaMap {
+	props := notion.PropertyMetaMap{}
+	if err := json.Unmarshal([]byte(schema), &props); err != nil {
+		panic(err)
+	}
+
+	return props
+}
 
 type PropertyValues struct {
 	Check         bool
-	MyDate        notion.Date
+	MyDate        database.DateValue
 	MyFiles       notion.Files
 	MyFloat       float32
 	MyMultiSelect notion.PropertyOptions
@@ -89,18 +198,1024 @@ type PropertyValues struct {
 }
 
 func GetPropertyValues(props notion.PropertyValueMap) PropertyValues {
+	return GetExactPropertyValues(props, nil, nil)
+}
+
+// GetExactPropertyValues returns the property values, using the exact numbers
+// and the raw values notion.PropertyValue can't hold, where they are given.
+func GetExactPropertyValues(props notion.PropertyValueMap, nums database.Numbers, raw database.RawValues) PropertyValues {
 	return PropertyValues{
 		Check:         props["Check"].GetCheckbox(),
-		MyDate:        props["My Date"].GetDate(),
+		MyDate:        database.NewDateValue(props["My Date"].GetDate()),
 		MyFiles:       props["My Files"].GetFiles(),
 		MyFloat:       props["My Float"].GetNumber(),
 		MyMultiSelect: props["My Multi Select"].GetMultiSelect(),
-		MyNumber:      int(props["My Number"].GetNumber()),
+		MyNumber:      int(database.Int64(props["My Number"], nums["My Number"])),
 		MyRelation:    props["My Relation"].GetRelation(),
 		MyRichtext:    props["My Richtext"].GetRichText(),
 		MySelect:      props["My Select"].GetSelect(),
 		MyTitle:       props["My Title"].GetTitle(),
 	}
 }
-`, string(b))
+
+// ToPropertyValueMap returns the property values as a property value map.
+func (v PropertyValues) ToPropertyValueMap() notion.PropertyValueMap {
+	return notion.PropertyValueMap{
+		"Check":           {Type: notion.PropertyTypeCheckbox, Checkbox: &v.Check},
+		"My Date":         {Type: notion.PropertyTypeDate, Date: v.MyDate.Date()},
+		"My Files":        {Type: notion.PropertyTypeFiles, Files: &v.MyFiles},
+		"My Float":        {Type: notion.PropertyTypeNumber, Number: database.Number(v.MyFloat)},
+		"My Multi Select": {Type: notion.PropertyTypeMultiSelect, MultiSelect: &v.MyMultiSelect},
+		"My Number":       {Type: notion.PropertyTypeNumber, Number: database.Number(v.MyNumber)},
+		"My Relation":     {Type: notion.PropertyTypeRelation, Relation: &v.MyRelation},
+		"My Richtext":     {Type: notion.PropertyTypeRichText, RichText: &v.MyRichtext},
+		"My Select":       {Type: notion.PropertyTypeSelect, Select: database.Select(v.MySelect)},
+		"My Title":        {Id: "title", Type: notion.PropertyTypeTitle, Title: &v.MyTitle},
+	}
+}
+
+// ToNumbers returns the exact numbers of the property values.
+func (v PropertyValues) ToNumbers() database.Numbers {
+	return database.Numbers{
+		"My Number": database.Int64Number(int64(v.MyNumber)),
+	}
+}
+
+// ToRawValues returns the raw values of the property values notion.PropertyValue can't hold.
+func (v PropertyValues) ToRawValues() database.RawValues {
+	return database.RawValues{}
+}
+
+// Diff returns the property values of to that differ from those of from,
+// as a property value map that can be used to update a page.
+func Diff(from, to PropertyValues) notion.PropertyValueMap {
+	return database.DiffRaw(from.ToPropertyValueMap(), to.ToPropertyValueMap(),
+		from.ToNumbers(), to.ToNumbers(), from.ToRawValues(), to.ToRawValues())
+}
+
+// Validate checks that the property values fit the schema of the database and its constraints.
+func (v PropertyValues) Validate() error {
+	val := &database.Validator{}
+	v.validate(val)
+
+	return val.Err()
+}
+
+// validate adds the reasons why the property values are invalid to the validator.
+func (v PropertyValues) validate(val *database.Validator) {
+	val.DateRange("My Date", v.MyDate)
+	val.Int("My Number", v.MyNumber)
+}
+`, withoutHeader(t, b))
+}
+
+func TestDatabases(t *testing.T) {
+	t.Parallel()
+	os.Stdout = nil
+
+	memFs := afero.NewMemMapFs()
+
+	relation := func(id notion.UUID) notion.PropertyMeta {
+		return notion.PropertyMeta{
+			Type:     notion.PropertyTypeRelation,
+			Relation: &notion.RelationConfiguration{DatabaseId: id},
+		}
+	}
+
+	require.NoError(t, gen.Databases(memFs, "github.com/user/myrepo/databases",
+		gen.Database{
+			PkgName: "tasks",
+			ID:      "8c5d2b1e-0f7a-4e3d-9b6c-1a2b3c4d5e6f",
+			Properties: notion.PropertyMetaMap{
+				"Name":      notion.TitleProperty,
+				"Project":   relation("3f2a1b0c9d8e7f6a5b4c3d2e1f0a9b8c"),
+				"Subtasks":  relation("8c5d2b1e-0f7a-4e3d-9b6c-1a2b3c4d5e6f"),
+				"Elsewhere": relation("00000000-0000-0000-0000-000000000000"),
+			},
+		},
+		gen.Database{
+			PkgName: "projects",
+			ID:      "3F2A1B0C-9D8E-7F6A-5B4C-3D2E1F0A9B8C",
+			Properties: notion.PropertyMetaMap{
+				"Name":  notion.TitleProperty,
+				"Tasks": relation("8c5d2b1e0f7a4e3d9b6c1a2b3c4d5e6f"),
+			},
+		}))
+
+	b, err := afero.ReadFile(memFs, "projects/projects.gen.go")
+	assert.NoError(t, err)
+
+	assert.Equal(t, `package projects
+
+import (
+	"encoding/json"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/user/myrepo/databases/tasks"
+)
+
+// The names of the properties in Notion.
+const (
+	PropName  = "Name"
+	PropTasks = "Tasks"
+)
+
+// The IDs of the properties in Notion, which don't change when the properties are renamed.
+const (
+	PropNameID = "title"
+)
+
+// schema holds the properties of the database the package was generated from.
+const schema = `+"`"+`{
+	"Name": {
+		"id": "title",
+		"name": "",
+		"title": {},
+		"type": "title"
+	},
+	"Tasks": {
+		"id": "",
+		"name": "",
+		"relation": {
+			"database_id": "8c5d2b1e0f7a4e3d9b6c1a2b3c4d5e6f",
+			"synced_property_name": ""
+		},
+		"type": "relation"
+	}
+}`+"`"+`
+
+// Schema returns the properties of the database the package was generated from.
+func Schema() notion.PropertyMetaMap {
+	props := notion.PropertyMetaMap{}
+	if err := json.Unmarshal([]byte(schema), &props); err != nil {
+		panic(err)
+	}
+
+	return props
+}
+
+type PropertyValues struct {
+	Name  notion.RichTexts
+	Tasks []tasks.ID
+}
+
+func GetPropertyValues(props notion.PropertyValueMap) PropertyValues {
+	return GetExactPropertyValues(props, nil, nil)
+}
+
+// GetExactPropertyValues returns the property values, using the exact numbers
+// and the raw values notion.PropertyValue can't hold, where they are given.
+func GetExactPropertyValues(props notion.PropertyValueMap, nums database.Numbers, raw database.RawValues) PropertyValues {
+	return PropertyValues{
+		Name:  props["Name"].GetTitle(),
+		Tasks: database.IDs[tasks.ID](props["Tasks"].GetRelation()),
+	}
+}
+
+// ToPropertyValueMap returns the property values as a property value map.
+func (v PropertyValues) ToPropertyValueMap() notion.PropertyValueMap {
+	return notion.PropertyValueMap{
+		"Name":  {Id: "title", Type: notion.PropertyTypeTitle, Title: &v.Name},
+		"Tasks": {Type: notion.PropertyTypeRelation, Relation: database.References(v.Tasks)},
+	}
+}
+
+// ToNumbers returns the exact numbers of the property values.
+func (v PropertyValues) ToNumbers() database.Numbers {
+	return database.Numbers{}
+}
+
+// ToRawValues returns the raw values of the property values notion.PropertyValue can't hold.
+func (v PropertyValues) ToRawValues() database.RawValues {
+	return database.RawValues{}
+}
+
+// Diff returns the property values of to that differ from those of from,
+// as a property value map that can be used to update a page.
+func Diff(from, to PropertyValues) notion.PropertyValueMap {
+	return database.DiffRaw(from.ToPropertyValueMap(), to.ToPropertyValueMap(),
+		from.ToNumbers(), to.ToNumbers(), from.ToRawValues(), to.ToRawValues())
+}
+
+// Validate checks that the property values fit the schema of the database and its constraints.
+func (v PropertyValues) Validate() error {
+	val := &database.Validator{}
+	v.validate(val)
+
+	return val.Err()
+}
+
+// validate adds the reasons why the property values are invalid to the validator.
+func (v PropertyValues) validate(val *database.Validator) {
+}
+`, withoutHeader(t, b))
+
+	b, err = afero.ReadFile(memFs, "tasks/tasks.gen.go")
+	assert.NoError(t, err)
+
+	// relating back to projects would lead to an import cycle
+	assert.Contains(t, string(b), "\tProject   notion.References\n")
+	assert.Contains(t, string(b), "\tElsewhere notion.References\n")
+	assert.Contains(t, string(b), "\tSubtasks  []ID\n")
+
+	b, err = afero.ReadFile(memFs, "tasks/entry.gen.go")
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(b), `// SubtasksEntries returns the entries "Subtasks" relates to.
+func (v PropertyValues) SubtasksEntries(ctx context.Context, cli *notion.Client) ([]Entry, error) {
+	entries := make([]Entry, len(v.Subtasks))
+
+	for i, id := range v.Subtasks {
+		e, err := GetEntry(ctx, cli, id)
+`)
+}
+
+func TestFixture(t *testing.T) {
+	t.Parallel()
+
+	memFs := afero.NewMemMapFs()
+
+	require.NoError(t, gen.PropertyValues(memFs, "mypackage",
+		notion.PropertyMetaMap{
+			"Name": notion.TitleProperty,
+			"Size": notion.PropertyMeta{
+				Type: notion.PropertyTypeSelect,
+				Select: &notion.PropertyOptionsWrapper{Options: []notion.PropertyOption{
+					{Name: "S"}, {Name: "M"}, {Name: "L"},
+				}},
+			},
+			"Count": notion.PropertyMeta{
+				Type:   notion.PropertyTypeNumber,
+				Number: &notion.NumberConfig{Format: notion.NumberConfigFormatNumber},
+			},
+		}))
+
+	b, err := afero.ReadFile(memFs, "mypackage/fixture.gen.go")
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(b), `// WithSize sets "Size" of a fixture.
+func WithSize(v notion.SelectValue) FixtureOption {
+	return func(f *PropertyValues) { f.Size = v }
+}`)
+
+	assert.Contains(t, string(b), `	return PropertyValues{
+		Count: r.Intn(1000),
+		Name:  database.RandomText(r, opts...),
+		Size:  database.RandomSelect(r, "S", "M", "L"),
+	}`)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	memFs := afero.NewMemMapFs()
+	one, ten := 1.0, 10.5
+
+	props := notion.PropertyMetaMap{
+		"Name": notion.TitleProperty,
+		"Size": notion.PropertyMeta{
+			Type: notion.PropertyTypeSelect,
+			Select: &notion.PropertyOptionsWrapper{Options: []notion.PropertyOption{
+				{Name: "S"}, {Name: "M"},
+			}},
+		},
+		"Count": notion.PropertyMeta{
+			Type:   notion.PropertyTypeNumber,
+			Number: &notion.NumberConfig{Format: notion.NumberConfigFormatNumber},
+		},
+		"Agreed": notion.PropertyMeta{
+			Type:     notion.PropertyTypeCheckbox,
+			Checkbox: emptyConfig,
+		},
+		"Notes": notion.PropertyMeta{
+			Type:     notion.PropertyTypeRichText,
+			RichText: emptyConfig,
+		},
+	}
+
+	require.NoError(t, gen.Databases(memFs, "", gen.Database{
+		PkgName:    "mypackage",
+		Properties: props,
+		Constraints: map[string]gen.Constraint{
+			"Name":   {Required: true, Pattern: `^[A-Z]`, MaxLength: 20},
+			"Count":  {Min: &one, Max: &ten},
+			"Agreed": {Required: true},
+			"Notes":  {MaxLength: 100},
+		},
+	}))
+
+	b, err := afero.ReadFile(memFs, "mypackage/mypackage.gen.go")
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(b), "var namePattern = regexp.MustCompile(\"^[A-Z]\")\n")
+	assert.Contains(t, string(b), `func (v PropertyValues) validate(val *database.Validator) {
+	val.Required("Agreed", v.Agreed)
+	val.Int("Count", v.Count)
+	val.Min("Count", float64(v.Count), 1)
+	val.Max("Count", float64(v.Count), 10.5)
+	val.Required("Name", v.Name.Content() != "")
+	val.Pattern("Name", v.Name.Content(), namePattern)
+	val.MaxLength("Name", v.Name.Content(), 20)
+	val.MaxLength("Notes", v.Notes.Content(), 100)
+	val.Option("Size", v.Size.Name, "S", "M")
+}`)
+
+	// fixtures fit the constraints
+	b, err = afero.ReadFile(memFs, "mypackage/fixture.gen.go")
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `	return PropertyValues{
+		Agreed: true,
+		Count:  int(database.RandomBetween(r, 1, 10.5, 0)),
+		Name:   database.RandomMatch(r, namePattern, 20),
+		Notes:  database.Truncate(database.RandomText(r, opts...), 100),
+		Size:   database.RandomSelect(r, "S", "M"),
+	}`)
+
+	b, err = afero.ReadFile(memFs, "mypackage/entry.gen.go")
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `func (e Entry) Validate() error {
+	val := &database.Validator{}
+	e.PropertyValues.validate(val)
+
+	nums := e.Numbers
+	val.Integral("Count", nums["Count"], int64(e.Count))
+
+	return val.Err()
+}`)
+
+	for name, c := range map[string]gen.Constraint{
+		"Count":   {Required: true},
+		"Name":    {Min: &one},
+		"Size":    {MaxLength: 3},
+		"Unknown": {Required: true},
+	} {
+		assert.Error(t, gen.Databases(memFs, "", gen.Database{
+			PkgName:     "mypackage",
+			Properties:  props,
+			Constraints: map[string]gen.Constraint{name: c},
+		}), name)
+	}
+
+	assert.Error(t, gen.Databases(memFs, "", gen.Database{
+		PkgName:     "mypackage",
+		Properties:  props,
+		Constraints: map[string]gen.Constraint{"Name": {Pattern: "("}},
+	}))
+}
+
+func TestPlain(t *testing.T) {
+	t.Parallel()
+
+	memFs := afero.NewMemMapFs()
+
+	props := notion.PropertyMetaMap{
+		"Name": notion.TitleProperty,
+		"Notes": notion.PropertyMeta{
+			Type:     notion.PropertyTypeRichText,
+			RichText: emptyConfig,
+		},
+		"Tags": notion.PropertyMeta{
+			Type:        notion.PropertyTypeMultiSelect,
+			MultiSelect: noOptions,
+		},
+		"Price": notion.PropertyMeta{
+			Type:   notion.PropertyTypeNumber,
+			Number: &notion.NumberConfig{Format: notion.NumberConfigFormatDollar},
+		},
+		"Due": notion.PropertyMeta{
+			Type: notion.PropertyTypeDate,
+			Date: emptyConfig,
+		},
+		"Done": notion.PropertyMeta{
+			Type:     notion.PropertyTypeCheckbox,
+			Checkbox: emptyConfig,
+		},
+	}
+
+	require.NoError(t, gen.Databases(memFs, "", gen.Database{
+		PkgName:         "mypackage",
+		Properties:      props,
+		Plain:           true,
+		PlainProperties: map[string]bool{"Notes": false},
+	}))
+
+	b, err := afero.ReadFile(memFs, "mypackage/mypackage.gen.go")
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(b), `type PropertyValues struct {
+	Done  bool
+	Due   time.Time
+	Name  string
+	Notes notion.RichTexts
+	Price float64
+	Tags  []string
+}
+
+func GetPropertyValues(props notion.PropertyValueMap) PropertyValues {
+	return GetExactPropertyValues(props, nil, nil)
+}
+
+// GetExactPropertyValues returns the property values, using the exact numbers
+// and the raw values notion.PropertyValue can't hold, where they are given.
+func GetExactPropertyValues(props notion.PropertyValueMap, nums database.Numbers, raw database.RawValues) PropertyValues {
+	return PropertyValues{
+		Done:  props["Done"].GetCheckbox(),
+		Due:   props["Due"].GetDate().Start,
+		Name:  props["Name"].GetTitle().Content(),
+		Notes: props["Notes"].GetRichText(),
+		Price: database.Float64(props["Price"], nums["Price"]),
+		Tags:  props["Tags"].GetMultiSelect().GetNames(),
+	}
+}
+
+// ToPropertyValueMap returns the property values as a property value map.
+func (v PropertyValues) ToPropertyValueMap() notion.PropertyValueMap {
+	return notion.PropertyValueMap{
+		"Done":  {Type: notion.PropertyTypeCheckbox, Checkbox: &v.Done},
+		"Due":   {Type: notion.PropertyTypeDate, Date: database.Time(v.Due)},
+		"Name":  {Id: "title", Type: notion.PropertyTypeTitle, Title: database.Text(v.Name)},
+		"Notes": {Type: notion.PropertyTypeRichText, RichText: &v.Notes},
+		"Price": {Type: notion.PropertyTypeNumber, Number: database.Number(v.Price)},
+		"Tags":  {Type: notion.PropertyTypeMultiSelect, MultiSelect: database.Options(v.Tags)},
+	}
+}`)
+
+	b, err = afero.ReadFile(memFs, "mypackage/fixture.gen.go")
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(b), `		Due:   database.RandomDate(r, opts...).Start,
+		Name:  database.RandomText(r, opts...).Content(),
+		Notes: database.RandomText(r, opts...),
+		Price: float64(database.RandomNumber(r)),
+		Tags:  database.RandomMultiSelect(r).GetNames(),`)
+
+	assert.Error(t, gen.Databases(memFs, "", gen.Database{
+		PkgName:         "mypackage",
+		Properties:      props,
+		PlainProperties: map[string]bool{"Done": true},
+	}))
+}
+
+func TestNumberFormats(t *testing.T) {
+	t.Parallel()
+
+	memFs := afero.NewMemMapFs()
+	max := 100.0
+
+	number := func(f notion.NumberConfigFormat) notion.PropertyMeta {
+		return notion.PropertyMeta{Type: notion.PropertyTypeNumber, Number: &notion.NumberConfig{Format: f}}
+	}
+
+	require.NoError(t, gen.Databases(memFs, "", gen.Database{
+		PkgName: "mypackage",
+		Properties: notion.PropertyMetaMap{
+			"Price":    number(notion.NumberConfigFormatYen),
+			"Progress": number(notion.NumberConfigFormatPercent),
+		},
+		Constraints: map[string]gen.Constraint{"Price": {Max: &max}},
+	}))
+
+	b, err := afero.ReadFile(memFs, "mypackage/mypackage.gen.go")
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(b), `type PropertyValues struct {
+	Price    database.Money
+	Progress database.Percent
+}
+
+func GetPropertyValues(props notion.PropertyValueMap) PropertyValues {
+	return GetExactPropertyValues(props, nil, nil)
+}
+
+// GetExactPropertyValues returns the property values, using the exact numbers
+// and the raw values notion.PropertyValue can't hold, where they are given.
+func GetExactPropertyValues(props notion.PropertyValueMap, nums database.Numbers, raw database.RawValues) PropertyValues {
+	return PropertyValues{
+		Price:    database.MoneyOf(props["Price"], nums["Price"], "JPY"),
+		Progress: database.Percent(database.Float64(props["Progress"], nums["Progress"])),
+	}
+}
+
+// ToPropertyValueMap returns the property values as a property value map.
+func (v PropertyValues) ToPropertyValueMap() notion.PropertyValueMap {
+	return notion.PropertyValueMap{
+		"Price":    {Type: notion.PropertyTypeNumber, Number: database.Number(v.Price.Float())},
+		"Progress": {Type: notion.PropertyTypeNumber, Number: database.Number(v.Progress)},
+	}
+}`)
+
+	assert.Contains(t, string(b), `	return database.Numbers{
+		"Price":    v.Price.Number(),
+		"Progress": database.Float64Number(float64(v.Progress)),
+	}`)
+
+	assert.Contains(t, string(b), `	val.Currency("Price", v.Price, "JPY")
+	val.Max("Price", v.Price.Float(), 100)`)
+
+	b, err = afero.ReadFile(memFs, "mypackage/fixture.gen.go")
+	assert.NoError(t, err)
+
+	// fixtures stay within the bounds
+	assert.Contains(t, string(b), `		Price:    database.NewMoney(database.RandomBetween(r, -900, 100, 0), "JPY"),
+		Progress: database.RandomPercent(r),`)
+}
+
+func TestNumberTypes(t *testing.T) {
+	t.Parallel()
+
+	memFs := afero.NewMemMapFs()
+
+	number := notion.PropertyMeta{Type: notion.PropertyTypeNumber, Number: &notion.NumberConfig{}}
+
+	require.NoError(t, gen.Databases(memFs, "", gen.Database{
+		PkgName: "mypackage",
+		Properties: notion.PropertyMetaMap{
+			"Count": number,
+			"Price": number,
+			"Score": number,
+		},
+		Numbers:     gen.NumberFloat64,
+		NumberTypes: map[string]gen.NumberType{"Count": gen.NumberInt64, "Price": gen.NumberDecimal},
+	}))
+
+	b, err := afero.ReadFile(memFs, "mypackage/mypackage.gen.go")
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(b), `type PropertyValues struct {
+	Count int64
+	Price database.Decimal
+	Score float64
+}`)
+
+	assert.Contains(t, string(b), `		Count: database.Int64(props["Count"], nums["Count"]),
+		Price: database.DecimalOf(props["Price"], nums["Price"]),
+		Score: database.Float64(props["Score"], nums["Score"]),`)
+
+	assert.Contains(t, string(b), `	return database.Numbers{
+		"Count": database.Int64Number(v.Count),
+		"Price": v.Price.Number(),
+		"Score": database.Float64Number(v.Score),
+	}`)
+
+	assert.Contains(t, string(b), `	val.Decimal("Price", v.Price)`)
+
+	err = gen.Databases(afero.NewMemMapFs(), "", gen.Database{
+		PkgName: "mypackage",
+		Properties: notion.PropertyMetaMap{
+			"Name": {Type: notion.PropertyTypeTitle},
+		},
+		NumberTypes: map[string]gen.NumberType{"Name": gen.NumberInt64},
+	})
+	assert.EqualError(t, err, `generating mypackage: title property "Name" is no number`)
+
+	err = gen.Databases(afero.NewMemMapFs(), "", gen.Database{
+		PkgName:     "mypackage",
+		Properties:  notion.PropertyMetaMap{"Count": number},
+		NumberTypes: map[string]gen.NumberType{"Count": "uint8"},
+	})
+	assert.Error(t, err)
+}
+
+func TestFormulas(t *testing.T) {
+	t.Parallel()
+
+	memFs := afero.NewMemMapFs()
+	formula := notion.PropertyMeta{Type: notion.PropertyTypeFormula}
+
+	require.NoError(t, gen.Databases(memFs, "", gen.Database{
+		PkgName: "mypackage",
+		Properties: notion.PropertyMetaMap{
+			"Any":  formula,
+			"Due":  formula,
+			"Late": formula,
+		},
+		FormulaTypes: map[string]database.FormulaType{
+			"Due":  database.FormulaDate,
+			"Late": database.FormulaBoolean,
+		},
+	}))
+
+	b, err := afero.ReadFile(memFs, "mypackage/mypackage.gen.go")
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(b), `type PropertyValues struct {
+	Any  database.Formula
+	Due  database.DateValue
+	Late bool
+}`)
+
+	assert.Contains(t, string(b), `	return PropertyValues{
+		Any:  database.NewFormula(raw["Any"]),
+		Due:  database.NewFormula(raw["Due"]).Date,
+		Late: database.NewFormula(raw["Late"]).Boolean,
+	}`)
+
+	// formulas can't be written
+	assert.Contains(t, string(b), `	return notion.PropertyValueMap{}`)
+
+	b, err = afero.ReadFile(memFs, "mypackage/fixture.gen.go")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "WithAny")
+
+	err = gen.Databases(afero.NewMemMapFs(), "", gen.Database{
+		PkgName:      "mypackage",
+		Properties:   notion.PropertyMetaMap{"Count": {Type: notion.PropertyTypeNumber}},
+		FormulaTypes: map[string]database.FormulaType{"Count": database.FormulaNumber},
+	})
+	assert.EqualError(t, err, `generating mypackage: number property "Count" is no formula`)
+
+	err = gen.Databases(afero.NewMemMapFs(), "", gen.Database{
+		PkgName:      "mypackage",
+		Properties:   notion.PropertyMetaMap{"Any": formula},
+		FormulaTypes: map[string]database.FormulaType{"Any": "array"},
+	})
+	assert.EqualError(t, err, `generating mypackage: property "Any": unknown formula type "array"`)
+}
+
+func TestRollups(t *testing.T) {
+	t.Parallel()
+
+	memFs := afero.NewMemMapFs()
+	rollup := notion.PropertyMeta{Type: notion.PropertyTypeRollup}
+	related := notion.PropertyMeta{
+		Type:     notion.PropertyTypeRelation,
+		Relation: &notion.RelationConfiguration{DatabaseId: "other-id"},
+	}
+
+	require.NoError(t, gen.Databases(memFs, "example.com/dbs", gen.Database{
+		PkgName: "mypackage",
+		Properties: notion.PropertyMetaMap{
+			"Any":     rollup,
+			"Count":   rollup,
+			"Dues":    rollup,
+			"Latest":  rollup,
+			"Names":   rollup,
+			"Others":  related,
+			"Outside": rollup,
+			"Unknown": {
+				Type:     notion.PropertyTypeRelation,
+				Relation: &notion.RelationConfiguration{DatabaseId: "unknown-id"},
+			},
+			"Amounts": rollup,
+		},
+		Rollups: map[string]gen.Rollup{
+			"Count":   {Relation: "Others", Property: "Name", Function: "count_all"},
+			"Latest":  {Relation: "Others", Property: "Due", Function: "latest_date"},
+			"Amounts": {Relation: "Others", Property: "Amount", Function: "show_original"},
+			"Dues":    {Relation: "Others", Property: "Due", Function: "show_unique"},
+			"Names":   {Relation: "Others", Property: "Name", Function: "show_original"},
+			"Outside": {Relation: "Unknown", Property: "Name", Function: "show_original"},
+		},
+	}, gen.Database{
+		PkgName: "other",
+		ID:      "other-id",
+		Properties: notion.PropertyMetaMap{
+			"Name":   {Type: notion.PropertyTypeTitle},
+			"Due":    {Type: notion.PropertyTypeDate},
+			"Amount": {Type: notion.PropertyTypeNumber},
+		},
+	}))
+
+	b, err := afero.ReadFile(memFs, "mypackage/mypackage.gen.go")
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(b), `type PropertyValues struct {
+	Amounts []float64
+	Any     database.Rollup
+	Count   float64
+	Dues    []database.DateValue
+	Latest  database.DateValue
+	Names   []notion.PropertyValue
+	Others  []other.ID
+	Outside database.Rollup
+	Unknown notion.References
+}`)
+
+	assert.Contains(t, string(b), `	return PropertyValues{
+		Amounts: database.NewRollup(raw["Amounts"]).Numbers(),
+		Any:     database.NewRollup(raw["Any"]),
+		Count:   database.NewRollup(raw["Count"]).Number,
+		Dues:    database.NewRollup(raw["Dues"]).Dates(),
+		Latest:  database.NewRollup(raw["Latest"]).Date,
+		Names:   database.NewRollup(raw["Names"]).Values(),`)
+
+	err = gen.Databases(afero.NewMemMapFs(), "", gen.Database{
+		PkgName:    "mypackage",
+		Properties: notion.PropertyMetaMap{"Count": {Type: notion.PropertyTypeNumber}},
+		Rollups:    map[string]gen.Rollup{"Count": {Function: "sum"}},
+	})
+	assert.EqualError(t, err, `generating mypackage: number property "Count" is no rollup`)
+
+	err = gen.Databases(afero.NewMemMapFs(), "", gen.Database{
+		PkgName:    "mypackage",
+		Properties: notion.PropertyMetaMap{"Count": rollup, "Name": {Type: notion.PropertyTypeTitle}},
+		Rollups:    map[string]gen.Rollup{"Count": {Relation: "Name", Function: "sum"}},
+	})
+	assert.EqualError(t, err, `generating mypackage: rollup "Count": "Name" is no relation`)
+
+	err = gen.Databases(afero.NewMemMapFs(), "", gen.Database{
+		PkgName:    "mypackage",
+		Properties: notion.PropertyMetaMap{"Count": rollup, "Others": related},
+		Rollups:    map[string]gen.Rollup{"Count": {Relation: "Others", Function: "product"}},
+	})
+	assert.EqualError(t, err, `generating mypackage: rollup "Count": unknown function "product"`)
+
+	err = gen.Databases(afero.NewMemMapFs(), "example.com/dbs", gen.Database{
+		PkgName:    "mypackage",
+		Properties: notion.PropertyMetaMap{"Count": rollup, "Others": related},
+		Rollups:    map[string]gen.Rollup{"Count": {Relation: "Others", Property: "Size", Function: "sum"}},
+	}, gen.Database{
+		PkgName:    "other",
+		ID:         "other-id",
+		Properties: notion.PropertyMetaMap{"Name": {Type: notion.PropertyTypeTitle}},
+	})
+	assert.EqualError(t, err, `generating mypackage: rollup "Count": other has no property "Size"`)
+}
+
+func TestPeople(t *testing.T) {
+	t.Parallel()
+
+	memFs := afero.NewMemMapFs()
+
+	require.NoError(t, gen.Databases(memFs, "", gen.Database{
+		PkgName: "mypackage",
+		Properties: notion.PropertyMetaMap{
+			"Owners":    {Type: notion.PropertyTypePeople},
+			"Author":    {Type: notion.PropertyTypeCreatedBy},
+			"Edited By": {Type: notion.PropertyTypeLastEditedBy},
+		},
+	}))
+
+	b, err := afero.ReadFile(memFs, "mypackage/mypackage.gen.go")
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(b), `type PropertyValues struct {
+	Author   notion.User
+	EditedBy notion.User
+	Owners   []notion.User
+}`)
+
+	assert.Contains(t, string(b), `	return PropertyValues{
+		Author:   database.NewUser(raw["Author"]),
+		EditedBy: database.NewUser(raw["Edited By"]),
+		Owners:   database.NewUsers(raw["Owners"]),
+	}`)
+
+	// users can't be written
+	assert.Contains(t, string(b), `	return notion.PropertyValueMap{}`)
+
+	b, err = afero.ReadFile(memFs, "mypackage/entry.gen.go")
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(b), `func (v PropertyValues) AuthorName(ctx context.Context, dir *database.Directory) (string, error) {
+	return dir.Name(ctx, v.Author)
+}`)
+	assert.Contains(t, string(b), `func (v PropertyValues) EditedByEmail(ctx context.Context, dir *database.Directory) (string, error) {`)
+	assert.Contains(t, string(b), `func (v PropertyValues) OwnersNames(ctx context.Context, dir *database.Directory) ([]string, error) {
+	return dir.Names(ctx, v.Owners)
+}`)
+	assert.Contains(t, string(b), `func (v PropertyValues) OwnersEmails(ctx context.Context, dir *database.Directory) ([]string, error) {`)
+}
+
+func TestFiles(t *testing.T) {
+	t.Parallel()
+
+	memFs := afero.NewMemMapFs()
+
+	require.NoError(t, gen.Databases(memFs, "", gen.Database{
+		PkgName:    "mypackage",
+		Properties: notion.PropertyMetaMap{"Attachments": {Type: notion.PropertyTypeFiles}},
+	}))
+
+	b, err := afero.ReadFile(memFs, "mypackage/entry.gen.go")
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(b), `"github.com/spf13/afero"`)
+	assert.Contains(t, string(b), `func (e Entry) DownloadAttachments(ctx context.Context, cli *notion.Client, fs afero.Fs, dir string,
+	opts ...database.DownloadOption,
+) (database.Manifest, error) {
+	return database.DownloadFiles(ctx, cli, fs, dir, notion.Id(e.ID), "Attachments", e.Attachments, opts...)
+}`)
+
+	require.NoError(t, gen.Databases(memFs, "", gen.Database{
+		PkgName:    "nofiles",
+		Properties: notion.PropertyMetaMap{"Name": {Type: notion.PropertyTypeTitle}},
+	}))
+
+	b, err = afero.ReadFile(memFs, "nofiles/entry.gen.go")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "afero")
+}
+
+func TestStatuses(t *testing.T) {
+	t.Parallel()
+
+	memFs := afero.NewMemMapFs()
+	status := notion.PropertyMeta{Type: database.PropertyTypeStatus}
+
+	require.NoError(t, gen.Databases(memFs, "", gen.Database{
+		PkgName:    "mypackage",
+		Properties: notion.PropertyMetaMap{"Any": status, "Phase": status},
+		Statuses: map[string]database.StatusConfig{
+			"Phase": {
+				Options: []notion.PropertyOption{{Id: "a", Name: "Not started"}, {Id: "b", Name: "Done"}},
+				Groups:  []database.StatusGroup{{Name: "To-do", OptionIds: []string{"a"}}},
+			},
+		},
+		Constraints: map[string]gen.Constraint{"Phase": {Required: true}},
+	}))
+
+	b, err := afero.ReadFile(memFs, "mypackage/mypackage.gen.go")
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(b), `// The options of "Phase".
+const (
+	PhaseNotStarted PhaseOption = "Not started"
+	PhaseDone       PhaseOption = "Done"
+)`)
+
+	assert.Contains(t, string(b), `func (o PhaseOption) Group() PhaseGroup {
+	switch o {
+	case PhaseNotStarted:
+		return PhaseGroupToDo
+	default:
+		return ""
+	}
+}`)
+
+	assert.Contains(t, string(b), `type PropertyValues struct {
+	Any   string
+	Phase PhaseOption
+}`)
+
+	assert.Contains(t, string(b), `		Any:   database.NewStatus(raw["Any"]).Name,
+		Phase: PhaseOption(database.NewStatus(raw["Phase"]).Name),`)
+
+	assert.Contains(t, string(b), `		"Any":   {Type: database.PropertyTypeStatus},
+		"Phase": {Type: database.PropertyTypeStatus},`)
+
+	assert.Contains(t, string(b), `	return database.RawValues{
+		"Any":   database.Status(v.Any),
+		"Phase": database.Status(string(v.Phase)),
+	}`)
+
+	assert.Contains(t, string(b), `	val.Required("Phase", v.Phase != "")
+	val.Option("Phase", string(v.Phase), "Not started", "Done")`)
+
+	assert.Contains(t, string(b), `var statusOptions = map[string]database.StatusConfig{
+	PropPhase: {Options: []notion.PropertyOption{
+		{Name: "Not started"},
+		{Name: "Done"},
+	}},
+}`)
+
+	b, err = afero.ReadFile(memFs, "mypackage/repository.gen.go")
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `database.CheckCompatibility(ctx, cli, id, Schema(), database.StatusOptions(statusOptions))`)
+
+	err = gen.Databases(afero.NewMemMapFs(), "", gen.Database{
+		PkgName:    "mypackage",
+		Properties: notion.PropertyMetaMap{"Phase": {Type: notion.PropertyTypeSelect}},
+		Statuses:   map[string]database.StatusConfig{"Phase": {}},
+	})
+	assert.EqualError(t, err, `generating mypackage: select property "Phase" is no status`)
+}
+
+func TestByID(t *testing.T) {
+	t.Parallel()
+
+	memFs := afero.NewMemMapFs()
+
+	require.NoError(t, gen.Databases(memFs, "", gen.Database{
+		PkgName: "mypackage",
+		Properties: notion.PropertyMetaMap{
+			"Name":  {Id: "title", Type: notion.PropertyTypeTitle},
+			"Notes": {Type: notion.PropertyTypeRichText},
+		},
+		ByID: true,
+	}))
+
+	b, err := afero.ReadFile(memFs, "mypackage/mypackage.gen.go")
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(b), `var propertyIDs = map[string]string{
+	PropName: PropNameID,
+}`)
+
+	assert.Contains(t, string(b), `	props, nums, raw = database.ByID(propertyIDs, props, nums, raw)`)
+
+	b, err = afero.ReadFile(memFs, "mypackage/repository.gen.go")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, strings.Count(string(b), `database.PropertyIDs(propertyIDs)`))
+}
+
+func TestIdentifiers(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name string
+		db   gen.Database
+		err  string
+	}{
+		{"names differing in punctuation", gen.Database{Properties: notion.PropertyMetaMap{
+			"Due Date": {Type: notion.PropertyTypeDate},
+			"Due-Date": {Type: notion.PropertyTypeDate},
+		}}, `property "Due Date" and property "Due-Date" would both be named DueDate`},
+		{"ID of another property", gen.Database{Properties: notion.PropertyMetaMap{
+			"Name":    {Id: "title", Type: notion.PropertyTypeTitle},
+			"Name ID": {Type: notion.PropertyTypeRichText},
+		}}, `property "Name" and property "Name ID" would be named PropNameID and PropNameId`},
+		{"status named like a generated type", gen.Database{
+			Properties: notion.PropertyMetaMap{"Fixture": {Type: database.PropertyTypeStatus}},
+			Statuses: map[string]database.StatusConfig{"Fixture": {
+				Options: []notion.PropertyOption{{Id: "a", Name: "Done"}},
+			}},
+		}, `the generated FixtureOption and property "Fixture" would both be named FixtureOption`},
+		{"option named like a group", gen.Database{
+			Properties: notion.PropertyMetaMap{"Stage": {Type: database.PropertyTypeStatus}},
+			Statuses: map[string]database.StatusConfig{"Stage": {
+				Options: []notion.PropertyOption{{Id: "a", Name: "Group X"}},
+				Groups:  []database.StatusGroup{{Name: "X", OptionIds: []string{"a"}}},
+			}},
+		}, `option "Group X" of property "Stage" and group "X" of property "Stage" would both be named StageGroupX`},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.db.PkgName = "mypackage"
+			assert.EqualError(t, gen.Databases(afero.NewMemMapFs(), "", tt.db), "generating mypackage: "+tt.err)
+		})
+	}
+}
+
+// withoutHeader checks that the generated file starts with a header and returns the rest of it.
+func withoutHeader(t *testing.T, b []byte) string {
+	t.Helper()
+
+	header, rest, ok := strings.Cut(string(b), "\n\n")
+	require.True(t, ok)
+	assert.Regexp(t, `^// Code generated by go-notion-codegen v\S+ from schema [0-9a-f]{64} with generator [0-9a-f]{64}; DO NOT EDIT\.$`, header)
+
+	return rest
+}
+
+func TestHeader(t *testing.T) {
+	t.Parallel()
+
+	memFs := afero.NewMemMapFs()
+	db := gen.Database{PkgName: "mypackage", Properties: notion.PropertyMetaMap{"Name": notion.TitleProperty}}
+
+	require.NoError(t, gen.Databases(memFs, "", db))
+
+	b, err := afero.ReadFile(memFs, "mypackage/fake.gen.go")
+	require.NoError(t, err)
+
+	header, _, _ := strings.Cut(string(b), "\n")
+	assert.Regexp(t, `^// Code generated by go-notion-codegen v`+regexp.QuoteMeta(notioncodegen.Version.String())+
+		` from schema [0-9a-f]{64} with generator [0-9a-f]{64}; DO NOT EDIT\.$`, header)
+
+	// unchanged input is skipped
+	edited := header + "\n\npackage mypackage\n"
+	require.NoError(t, afero.WriteFile(memFs, "mypackage/fake.gen.go", []byte(edited), 0o644))
+	require.NoError(t, gen.Databases(memFs, "", db))
+
+	b, err = afero.ReadFile(memFs, "mypackage/fake.gen.go")
+	require.NoError(t, err)
+	assert.Equal(t, edited, string(b))
+
+	// changed input is generated again
+	db.Plain = true
+	require.NoError(t, gen.Databases(memFs, "", db))
+
+	b, err = afero.ReadFile(memFs, "mypackage/fake.gen.go")
+	require.NoError(t, err)
+	assert.NotEqual(t, edited, string(b))
+
+	// only the fingerprint of the schema changed
+	changed, _, _ := strings.Cut(string(b), "\n")
+	schema, generator, _ := strings.Cut(header, " with generator")
+	changedSchema, changedGenerator, _ := strings.Cut(changed, " with generator")
+	assert.NotEqual(t, schema, changedSchema)
+	assert.Equal(t, generator, changedGenerator)
+
+	// files without a fingerprint of the generator are generated again
+	old := changedSchema + "; DO NOT EDIT.\n\npackage mypackage\n"
+	require.NoError(t, afero.WriteFile(memFs, "mypackage/fake.gen.go", []byte(old), 0o644))
+	require.NoError(t, gen.Databases(memFs, "", db))
+
+	b, err = afero.ReadFile(memFs, "mypackage/fake.gen.go")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(b), changed+"\n"))
+
+	// output of newer generators is left alone
+	newer := "// Code generated by go-notion-codegen v99.0.0 from schema abc; DO NOT EDIT.\n\npackage mypackage\n"
+	require.NoError(t, afero.WriteFile(memFs, "mypackage/entry.gen.go", []byte(newer), 0o644))
+
+	err = gen.Databases(memFs, "", db)
+	assert.EqualError(t, err, "generating mypackage: mypackage/entry.gen.go was generated by go-notion-codegen v99.0.0, "+
+		"which is newer than v"+notioncodegen.Version.String())
 }
@@ -0,0 +1,174 @@
+package gen
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+
+	"github.com/MarkRosemaker/semver"
+	"github.com/faetools/go-notion-codegen/notioncodegen"
+	"github.com/spf13/afero"
+)
+
+// generator is the code of the generator, which the generated code depends on as much as on the templates.
+//
+//go:embed generate.go header.go
+var generator embed.FS
+
+// reHeader matches the header of generated files.
+// Files of earlier versions have no fingerprint of the generator.
+var reHeader = regexp.MustCompile(
+	`^// Code generated by go-notion-codegen v(\S+) from schema ([0-9a-f]+)(?: with generator ([0-9a-f]+))?; DO NOT EDIT\.$`)
+
+// header is the first line of generated files, which tells the version of the generator
+// and the fingerprints of the schema and the generator they were generated from.
+type header struct {
+	Version *semver.Version
+	// Schema is a hash of the database, what its relations and rollups were resolved to.
+	Schema string
+	// Generator is a hash of the templates and the code of the generator.
+	Generator string
+}
+
+func (h header) String() string {
+	return fmt.Sprintf("// Code generated by go-notion-codegen v%s from schema %s with generator %s; DO NOT EDIT.",
+		h.Version, h.Schema, h.Generator)
+}
+
+// readHeader returns the header of the generated file and whether it has one.
+// Files that don't exist have none.
+func readHeader(fs afero.Fs, path string) (header, bool, error) {
+	f, err := fs.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return header{}, false, nil
+	}
+
+	if err != nil {
+		return header{}, false, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	if !s.Scan() {
+		return header{}, false, s.Err()
+	}
+
+	m := reHeader.FindStringSubmatch(s.Text())
+	if m == nil {
+		return header{}, false, nil
+	}
+
+	v, err := semver.NewVersion(m[1])
+	if err != nil {
+		return header{}, false, fmt.Errorf("reading header of %s: %w", path, err)
+	}
+
+	return header{Version: v, Schema: m[2], Generator: m[3]}, true, nil
+}
+
+// header returns the header of the files generated for the package.
+//
+// Its fingerprints cover everything the generated code depends on besides the generator version:
+// the schema of the database, what its relations and rollups were resolved to,
+// and the templates and the code of the generator.
+func (p pkg) header() (header, error) {
+	targets := map[string]*target{}
+	rolledUp := map[string]string{}
+
+	for _, prop := range p.props {
+		if prop.target != nil {
+			targets[prop.Key] = prop.target
+		}
+
+		if prop.rolledUp != "" {
+			rolledUp[prop.Key] = string(prop.rolledUp)
+		}
+	}
+
+	b, err := json.Marshal(struct {
+		Database Database
+		Targets  map[string]*target
+		RolledUp map[string]string
+	}{p.Database, targets, rolledUp})
+	if err != nil {
+		return header{}, fmt.Errorf("fingerprinting %s: %w", p.PkgName, err)
+	}
+
+	schema := sha256.Sum256(b)
+
+	code, err := fingerprintGenerator()
+	if err != nil {
+		return header{}, fmt.Errorf("fingerprinting %s: %w", p.PkgName, err)
+	}
+
+	return header{
+		Version:   notioncodegen.Version,
+		Schema:    hex.EncodeToString(schema[:]),
+		Generator: code,
+	}, nil
+}
+
+// fingerprintGenerator returns a hash of the templates and the code of the generator,
+// so that changes to the generator that keep its version are not skipped.
+func fingerprintGenerator() (string, error) {
+	h := sha256.New()
+
+	for _, tpl := range []string{
+		tplImportsRaw, tplPropertyValuesRaw, tplEntryRaw,
+		tplRepositoryRaw, tplFakeRaw, tplFixtureRaw,
+	} {
+		h.Write([]byte(tpl))
+	}
+
+	if err := fs.WalkDir(generator, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		src, err := generator.ReadFile(path)
+		h.Write(src)
+
+		return err
+	}); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// upToDate reports whether all files were generated by the same version and code of the generator
+// from the same schema, so that they need not be generated again.
+// It returns an error if any of them was generated by a newer version.
+func upToDate(fs afero.Fs, h header, paths []string) (bool, error) {
+	same := true
+
+	for _, path := range paths {
+		existing, ok, err := readHeader(fs, path)
+		if err != nil {
+			return false, err
+		}
+
+		if !ok {
+			same = false
+			continue
+		}
+
+		if existing.Version.GreaterThan(h.Version) {
+			return false, fmt.Errorf("%s was generated by go-notion-codegen v%s, which is newer than v%s",
+				path, existing.Version, h.Version)
+		}
+
+		if !existing.Version.Equal(h.Version) || existing.Schema != h.Schema || existing.Generator != h.Generator {
+			same = false
+		}
+	}
+
+	return same, nil
+}
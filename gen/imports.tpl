@@ -0,0 +1,11 @@
+{{ define "imports" -}}
+{{ if eq (len .) 1 -}}
+import "{{ index . 0 }}"
+{{- else -}}
+import (
+{{- range . }}
+	"{{ . }}"
+{{- end }}
+)
+{{- end }}
+{{- end }}
@@ -1,7 +1,90 @@
 package {{ .PkgName }}
 
-import "github.com/faetools/go-notion/pkg/notion"
+{{ template "imports" .Imports }}
 
+{{- range .Properties }}{{ if .Pattern }}
+var {{ .PatternVar }} = regexp.MustCompile({{ printf "%q" .Pattern }})
+{{ end }}{{ end }}
+{{- if .Properties }}
+// The names of the properties in Notion.
+const (
+{{- range .Properties }}
+	{{ .Const }} = {{ printf "%q" .Key }}
+{{- end }}
+)
+{{ end }}{{ if .PropertyIDs }}
+// The IDs of the properties in Notion, which don't change when the properties are renamed.
+const (
+{{- range .PropertyIDs }}
+	{{ .Const }}ID = {{ printf "%q" .ID }}
+{{- end }}
+)
+{{ end }}
+{{- range .Properties }}{{ if .KnownStatus }}{{ $p := . }}
+// {{ .Name }}Option is an option of {{ .Key | printf "%q" }}.
+type {{ .Name }}Option string
+{{ if .StatusOptions }}
+// The options of {{ .Key | printf "%q" }}.
+const (
+{{- range .StatusOptions }}
+	{{ .Const }} {{ $p.Name }}Option = {{ printf "%q" .Value }}
+{{- end }}
+)
+{{ end }}{{ if .StatusGroups }}
+// {{ .Name }}Group is a group of options of {{ .Key | printf "%q" }}.
+type {{ .Name }}Group string
+
+// The groups of options of {{ .Key | printf "%q" }}.
+const (
+{{- range .StatusGroups }}
+	{{ .Const }} {{ $p.Name }}Group = {{ printf "%q" .Value }}
+{{- end }}
+)
+
+// Group returns the group the option belongs to.
+func (o {{ .Name }}Option) Group() {{ .Name }}Group {
+	switch o {
+{{- range .StatusOptions }}{{ if .Group }}
+	case {{ .Const }}:
+		return {{ $p.GroupConst .Group }}
+{{- end }}{{ end }}
+	default:
+		return ""
+	}
+}
+{{ end }}{{ end }}{{ end }}{{ if .ByID }}
+// propertyIDs are the IDs of the properties by name,
+// which property values are found by and written with.
+var propertyIDs = map[string]string{
+{{- range .PropertyIDs }}
+	{{ .Const }}: {{ .Const }}ID,
+{{- end }}
+}
+{{ end }}
+// schema holds the properties of the database the package was generated from.
+const schema = {{ .Schema }}
+
+// Schema returns the properties of the database the package was generated from.
+func Schema() notion.PropertyMetaMap {
+	props := notion.PropertyMetaMap{}
+	if err := json.Unmarshal([]byte(schema), &props); err != nil {
+		panic(err)
+	}
+
+	return props
+}
+{{ if .Statuses }}
+// statusOptions are the options of the statuses the package knows, which the schema can't hold.
+var statusOptions = map[string]database.StatusConfig{
+{{- range .Statuses }}
+	{{ .Const }}: {Options: []notion.PropertyOption{
+	{{- range .StatusOptions }}
+		{Name: {{ printf "%q" .Value }}},
+	{{- end }}
+	}},
+{{- end }}
+}
+{{ end }}
 type PropertyValues struct {
 {{- range .Properties }}
 	{{ .Name }} {{ .GoType -}}
@@ -9,12 +92,67 @@ type PropertyValues struct {
 }
 
 func GetPropertyValues(props notion.PropertyValueMap) PropertyValues {
+	return GetExactPropertyValues(props, nil, nil)
+}
+
+// GetExactPropertyValues returns the property values, using the exact numbers
+// and the raw values notion.PropertyValue can't hold, where they are given.
+func GetExactPropertyValues(props notion.PropertyValueMap, nums database.Numbers, raw database.RawValues) PropertyValues {
+{{- if .ByID }}
+	props, nums, raw = database.ByID(propertyIDs, props, nums, raw)
+{{ end }}
 	return PropertyValues{
 	{{- range .Properties }}
-		{{ .Name }}:
-			{{- if .IsInt }}int({{ end -}}
-			props["{{ .Key }}"].{{ .GetFunc }}
-			{{- if .IsInt -}}){{ end }},
+		{{ .Name }}: {{ .Getter }},
 	{{- end }}
 	}
 }
+
+// ToPropertyValueMap returns the property values as a property value map.
+func (v PropertyValues) ToPropertyValueMap() notion.PropertyValueMap {
+	return notion.PropertyValueMap{
+	{{- range .Properties }}{{ if .Encoded }}
+		{{ printf "%q" .Key }}: { {{- .Encoded -}} },
+	{{- end }}{{ end }}
+	}
+}
+
+// ToNumbers returns the exact numbers of the property values.
+func (v PropertyValues) ToNumbers() database.Numbers {
+	return database.Numbers{
+	{{- range .Properties }}{{ if .ExactNumber }}
+		{{ printf "%q" .Key }}: {{ .ExactNumber }},
+	{{- end }}{{ end }}
+	}
+}
+
+// ToRawValues returns the raw values of the property values notion.PropertyValue can't hold.
+func (v PropertyValues) ToRawValues() database.RawValues {
+	return database.RawValues{
+	{{- range .Properties }}{{ if .RawValue }}
+		{{ printf "%q" .Key }}: {{ .RawValue }},
+	{{- end }}{{ end }}
+	}
+}
+
+// Diff returns the property values of to that differ from those of from,
+// as a property value map that can be used to update a page.
+func Diff(from, to PropertyValues) notion.PropertyValueMap {
+	return database.DiffRaw(from.ToPropertyValueMap(), to.ToPropertyValueMap(),
+		from.ToNumbers(), to.ToNumbers(), from.ToRawValues(), to.ToRawValues())
+}
+
+// Validate checks that the property values fit the schema of the database and its constraints.
+func (v PropertyValues) Validate() error {
+	val := &database.Validator{}
+	v.validate(val)
+
+	return val.Err()
+}
+
+// validate adds the reasons why the property values are invalid to the validator.
+func (v PropertyValues) validate(val *database.Validator) {
+{{- range .Properties }}{{ range .Checks }}
+	{{ . }}
+{{- end }}{{ end }}
+}
@@ -6,6 +6,7 @@ require (
 	github.com/ettle/strcase v0.1.1
 	github.com/faetools/cgtools v0.0.4
 	github.com/faetools/go-notion v0.0.16
+	github.com/google/uuid v1.3.0
 	github.com/spf13/afero v1.8.2
 	github.com/stretchr/testify v1.8.0
 )
@@ -24,7 +25,6 @@ require (
 	github.com/gogo/protobuf v1.3.2 // indirect
 	github.com/golang/protobuf v1.5.2 // indirect
 	github.com/google/go-cmp v0.5.8 // indirect
-	github.com/google/uuid v1.3.0 // indirect
 	github.com/hashicorp/hcl v1.0.0 // indirect
 	github.com/logrusorgru/aurora v2.0.3+incompatible // indirect
 	github.com/magiconair/properties v1.8.6 // indirect
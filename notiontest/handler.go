@@ -0,0 +1,455 @@
+package notiontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion-codegen/query"
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// parent is the parent of a page or database.
+// Unlike notion.Parent, it can hold a database.
+type parent struct {
+	Type       string      `json:"type"`
+	DatabaseID notion.UUID `json:"database_id,omitempty"`
+	PageID     notion.UUID `json:"page_id,omitempty"`
+}
+
+// page is a page as Notion sends it.
+type page struct {
+	database.Page
+	Parent parent `json:"parent"`
+}
+
+// MarshalJSON encodes the page with its exact numbers, its raw values and its parent.
+func (p page) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(p.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	m := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	if m["parent"], err = json.Marshal(p.Parent); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(m)
+}
+
+// databaseQuery is a query of a database.
+// Unlike notion.DatabaseQuery, it can hold all filters.
+type databaseQuery struct {
+	Filter      *query.Filter `json:"filter"`
+	Sorts       query.Sorts   `json:"sorts"`
+	StartCursor *notion.UUID  `json:"start_cursor"`
+	PageSize    int           `json:"page_size"`
+}
+
+// list is a paginated list of objects.
+type list[T any] struct {
+	Object     string  `json:"object"`
+	Type       string  `json:"type"`
+	Results    []T     `json:"results"`
+	NextCursor *string `json:"next_cursor"`
+	HasMore    bool    `json:"has_more"`
+}
+
+func newList[T any](typ string, results []T, next string) list[T] {
+	l := list[T]{Object: "list", Type: typ, Results: results}
+
+	if next != "" {
+		l.NextCursor, l.HasMore = &next, true
+	}
+
+	return l
+}
+
+// errNotFound is returned when an object is unknown.
+var errNotFound = &notion.Error{
+	Object:  "error",
+	Status:  http.StatusNotFound,
+	Code:    "object_not_found",
+	Message: "Could not find object.",
+}
+
+func badRequest(code string, err error) *notion.Error {
+	return &notion.Error{
+		Object:  "error",
+		Status:  http.StatusBadRequest,
+		Code:    code,
+		Message: err.Error(),
+	}
+}
+
+func invalidURL(r *http.Request) *notion.Error {
+	return badRequest("invalid_request_url", fmt.Errorf("invalid request URL: %s %s", r.Method, r.URL.Path))
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res, notionErr := s.route(r)
+	if notionErr != nil {
+		write(w, notionErr.Status, notionErr)
+		return
+	}
+
+	write(w, http.StatusOK, res)
+}
+
+func write(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// route handles the request and returns the response or the error Notion would respond with.
+func (s *Server) route(r *http.Request) (interface{}, *notion.Error) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "v1" {
+		return nil, invalidURL(r)
+	}
+
+	parts = append(parts[1:], "", "")
+	resource, id, sub := parts[0], notion.UUID(parts[1]), parts[2]
+
+	switch {
+	case resource == "databases" && id == "" && r.Method == http.MethodPost:
+		return s.createDatabase(r)
+	case resource == "databases" && sub == "" && r.Method == http.MethodGet:
+		return s.getDatabase(id)
+	case resource == "databases" && sub == "" && r.Method == http.MethodPatch:
+		return s.updateDatabase(r, id)
+	case resource == "databases" && sub == "query" && r.Method == http.MethodPost:
+		return s.queryDatabase(r, id)
+	case resource == "pages" && id == "" && r.Method == http.MethodPost:
+		return s.createPage(r)
+	case resource == "pages" && sub == "" && r.Method == http.MethodGet:
+		return s.getPage(id)
+	case resource == "pages" && sub == "" && r.Method == http.MethodPatch:
+		return s.updatePage(r, id)
+	case resource == "blocks" && sub == "children" && r.Method == http.MethodGet:
+		return s.getBlocks(r, id)
+	case resource == "blocks" && sub == "children" && r.Method == http.MethodPatch:
+		return s.appendBlockChildren(r, id)
+	case resource == "users" && id == "" && r.Method == http.MethodGet:
+		return s.listUsers(r)
+	case resource == "users" && id == "me" && r.Method == http.MethodGet:
+		return s.bot, nil
+	case resource == "users" && sub == "" && r.Method == http.MethodGet:
+		return s.getUser(id)
+	default:
+		return nil, invalidURL(r)
+	}
+}
+
+// decode decodes the body into all given values.
+func decode(r *http.Request, vs ...interface{}) *notion.Error {
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return badRequest("invalid_json", fmt.Errorf("reading body: %w", err))
+	}
+
+	for _, v := range vs {
+		if err := json.Unmarshal(b, v); err != nil {
+			return badRequest("invalid_json", fmt.Errorf("body failed to parse: %w", err))
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) getDatabase(id notion.UUID) (interface{}, *notion.Error) {
+	db, ok := s.databases[database.NormalizeID(id)]
+	if !ok {
+		return nil, errNotFound
+	}
+
+	return db, nil
+}
+
+func (s *Server) createDatabase(r *http.Request) (interface{}, *notion.Error) {
+	body := notion.Database{}
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+
+	if body.Parent == nil || body.Parent.PageId == "" {
+		return nil, badRequest("validation_error", fmt.Errorf("body.parent.page_id should be defined"))
+	}
+
+	return s.addDatabase(notion.Database{
+		Parent:     body.Parent,
+		Title:      body.Title,
+		Properties: body.Properties,
+	}), nil
+}
+
+func (s *Server) updateDatabase(r *http.Request, id notion.UUID) (interface{}, *notion.Error) {
+	db, ok := s.databases[database.NormalizeID(id)]
+	if !ok {
+		return nil, errNotFound
+	}
+
+	body := notion.Database{}
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+
+	if len(body.Title) > 0 {
+		db.Title = body.Title
+	}
+
+	for key, meta := range body.Properties {
+		name, old, ok := lookup(db, key)
+		if !ok {
+			name = key
+		}
+
+		delete(db.Properties, name)
+
+		// properties are removed by setting them to null
+		if meta.Type == "" && meta.Name == "" {
+			continue
+		}
+
+		if meta.Name != "" {
+			name = meta.Name
+		}
+
+		if meta.Type == "" {
+			meta.Type = old.Type
+		}
+
+		meta.Id = old.Id
+		db.Properties[name] = withName(name, meta)
+	}
+
+	db.LastEditedTime = time.Now().UTC()
+	s.databases[database.NormalizeID(id)] = db
+
+	return db, nil
+}
+
+func (s *Server) queryDatabase(r *http.Request, id notion.UUID) (interface{}, *notion.Error) {
+	db, ok := s.databases[database.NormalizeID(id)]
+	if !ok {
+		return nil, errNotFound
+	}
+
+	q := databaseQuery{}
+	if err := decode(r, &q); err != nil {
+		return nil, err
+	}
+
+	if q.Filter != nil {
+		if err := checkFilter(db, *q.Filter); err != nil {
+			return nil, badRequest("validation_error", err)
+		}
+	}
+
+	cursor := ""
+	if q.StartCursor != nil {
+		cursor = string(*q.StartCursor)
+	}
+
+	results, next, err := paginate(s.queryPages(db, q),
+		func(p database.Page) notion.UUID { return p.Id }, cursor, q.PageSize)
+	if err != nil {
+		return nil, badRequest("validation_error", err)
+	}
+
+	pages := make([]page, len(results))
+	for i, p := range results {
+		pages[i] = s.page(p.Page)
+	}
+
+	return newList("page", pages, next), nil
+}
+
+// page returns the page with its parent. The lock must be held.
+func (s *Server) page(p notion.Page) page {
+	id := database.NormalizeID(p.Id)
+
+	return page{
+		Page:   database.Page{Page: p, Numbers: s.numbers[id], Raw: s.raw[id]},
+		Parent: parent{Type: "database_id", DatabaseID: s.parents[id]},
+	}
+}
+
+func (s *Server) getPage(id notion.UUID) (interface{}, *notion.Error) {
+	p, ok := s.pages[database.NormalizeID(id)]
+	if !ok {
+		return nil, errNotFound
+	}
+
+	return s.page(p), nil
+}
+
+func (s *Server) createPage(r *http.Request) (interface{}, *notion.Error) {
+	body := struct {
+		Parent   parent        `json:"parent"`
+		Children notion.Blocks `json:"children"`
+		Icon     *notion.Icon  `json:"icon"`
+		Cover    *notion.File  `json:"cover"`
+	}{}
+	// the property values are decoded with their exact numbers and dates
+	props := database.Page{}
+
+	if err := decode(r, &body, &props); err != nil {
+		return nil, err
+	}
+
+	if body.Parent.DatabaseID == "" {
+		return nil, badRequest("validation_error", fmt.Errorf("body.parent.database_id should be defined"))
+	}
+
+	db, ok := s.databases[database.NormalizeID(body.Parent.DatabaseID)]
+	if !ok {
+		return nil, errNotFound
+	}
+
+	if err := checkProperties(db, props.Properties); err != nil {
+		return nil, badRequest("validation_error", err)
+	}
+
+	p := s.addPage(db, notion.Page{Properties: props.Properties, Icon: body.Icon, Cover: body.Cover})
+	s.numbers[database.NormalizeID(p.Id)] = withValues(db, nil, props.Numbers)
+	s.raw[database.NormalizeID(p.Id)] = withValues(db, nil, props.Raw)
+	s.appendBlocks(p.Id, body.Children)
+
+	return s.page(p), nil
+}
+
+func (s *Server) updatePage(r *http.Request, id notion.UUID) (interface{}, *notion.Error) {
+	p, ok := s.pages[database.NormalizeID(id)]
+	if !ok {
+		return nil, errNotFound
+	}
+
+	body := struct {
+		Archived *bool        `json:"archived"`
+		Icon     *notion.Icon `json:"icon"`
+		Cover    *notion.File `json:"cover"`
+	}{}
+	props := database.Page{}
+
+	if err := decode(r, &body, &props); err != nil {
+		return nil, err
+	}
+
+	db := s.databases[database.NormalizeID(s.parents[database.NormalizeID(id)])]
+
+	if err := checkProperties(db, props.Properties); err != nil {
+		return nil, badRequest("validation_error", err)
+	}
+
+	p.Properties = withSchema(db, p.Properties, props.Properties)
+	s.numbers[database.NormalizeID(id)] = withValues(db, s.numbers[database.NormalizeID(id)], props.Numbers)
+	s.raw[database.NormalizeID(id)] = withValues(db, s.raw[database.NormalizeID(id)], props.Raw)
+
+	if body.Archived != nil {
+		p.Archived = *body.Archived
+	}
+
+	if body.Icon != nil {
+		p.Icon = body.Icon
+	}
+
+	if body.Cover != nil {
+		p.Cover = body.Cover
+	}
+
+	p.LastEditedTime = time.Now().UTC()
+	s.pages[database.NormalizeID(id)] = p
+
+	return s.page(p), nil
+}
+
+// hasBlock reports whether a page or block with the ID exists. The lock must be held.
+func (s *Server) hasBlock(id notion.UUID) bool {
+	if _, ok := s.pages[database.NormalizeID(id)]; ok {
+		return true
+	}
+
+	if _, ok := s.blocks[database.NormalizeID(id)]; ok {
+		return true
+	}
+
+	for _, blocks := range s.blocks {
+		for _, b := range blocks {
+			if database.NormalizeID(b.Id) == database.NormalizeID(id) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (s *Server) getBlocks(r *http.Request, id notion.UUID) (interface{}, *notion.Error) {
+	if !s.hasBlock(id) {
+		return nil, errNotFound
+	}
+
+	blocks, next, err := paginate(s.blocks[database.NormalizeID(id)],
+		func(b notion.Block) notion.UUID { return b.Id }, r.URL.Query().Get("start_cursor"), pageSize(r))
+	if err != nil {
+		return nil, badRequest("validation_error", err)
+	}
+
+	return newList("block", blocks, next), nil
+}
+
+func (s *Server) appendBlockChildren(r *http.Request, id notion.UUID) (interface{}, *notion.Error) {
+	if !s.hasBlock(id) {
+		return nil, errNotFound
+	}
+
+	body := notion.BlocksChildren{}
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+
+	s.appendBlocks(id, body.Children)
+
+	blocks, next, _ := paginate(s.blocks[database.NormalizeID(id)],
+		func(b notion.Block) notion.UUID { return b.Id }, "", maxPageSize)
+
+	return newList("block", blocks, next), nil
+}
+
+func (s *Server) listUsers(r *http.Request) (interface{}, *notion.Error) {
+	users, next, err := paginate(s.users,
+		func(u notion.User) notion.UUID { return u.Id }, r.URL.Query().Get("start_cursor"), pageSize(r))
+	if err != nil {
+		return nil, badRequest("validation_error", err)
+	}
+
+	return newList("user", users, next), nil
+}
+
+func (s *Server) getUser(id notion.UUID) (interface{}, *notion.Error) {
+	for _, u := range s.users {
+		if database.NormalizeID(u.Id) == database.NormalizeID(id) {
+			return u, nil
+		}
+	}
+
+	if database.NormalizeID(s.bot.Id) == database.NormalizeID(id) {
+		return s.bot, nil
+	}
+
+	return nil, errNotFound
+}
@@ -0,0 +1,400 @@
+// Package notiontest provides a local stand-in for the Notion API to be used in tests.
+package notiontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/faetools/client"
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion-codegen/query"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/google/uuid"
+)
+
+const maxPageSize = 100
+
+// Server is an HTTP server that implements the part of the Notion API a notion.Client uses:
+// retrieving, querying, creating and updating databases,
+// retrieving, creating and updating pages, block children and users.
+//
+// Everything is held in memory.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	databases map[string]notion.Database
+	pages     map[string]notion.Page
+	// numbers holds the exact numbers of each page, as notion.Page only holds float32.
+	numbers map[string]database.Numbers
+	// raw holds the values of each page notion.Page can't hold, e.g. statuses.
+	raw map[string]database.RawValues
+	// statuses holds the options of the statuses of each database, which its schema can't hold.
+	statuses map[string]map[string]database.StatusConfig
+	// parents holds the ID of the database each page belongs to.
+	parents map[string]notion.UUID
+	// order holds the IDs of all pages in the order they were added.
+	order  []string
+	blocks map[string]notion.Blocks
+	users  notion.Users
+	bot    notion.User
+}
+
+// NewServer starts and returns a new server. The caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		databases: map[string]notion.Database{},
+		pages:     map[string]notion.Page{},
+		numbers:   map[string]database.Numbers{},
+		raw:       map[string]database.RawValues{},
+		statuses:  map[string]map[string]database.StatusConfig{},
+		parents:   map[string]notion.UUID{},
+		blocks:    map[string]notion.Blocks{},
+		users:     notion.Users{},
+		bot: notion.User{
+			Object: "user",
+			Id:     notion.UUID(uuid.NewString()),
+			Type:   notion.UserTypeBot,
+			Name:   "notiontest",
+		},
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// Client returns a client that sends its requests to the server.
+func (s *Server) Client(opts ...client.Option) (*notion.Client, error) {
+	return notion.NewDefaultClient("secret", append([]client.Option{client.WithBaseURL(s.URL)}, opts...)...)
+}
+
+// AddDatabase adds a database with the given properties and returns it.
+// If the ID is empty, a random one is used.
+func (s *Server) AddDatabase(id notion.UUID, title string, props notion.PropertyMetaMap) notion.Database {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addDatabase(notion.Database{Id: id, Title: notion.NewRichTexts(title), Properties: props})
+}
+
+// SetStatusOptions sets the options of the statuses of the database with the given ID, by property name.
+// The schema can't hold them, but pages are sorted by their order.
+func (s *Server) SetStatusOptions(dbID notion.UUID, statuses map[string]database.StatusConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.statuses[database.NormalizeID(dbID)] = statuses
+}
+
+// AddPages adds the pages to the database with the given ID.
+// Pages without an ID get a random one.
+func (s *Server) AddPages(dbID notion.UUID, pages ...notion.Page) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	db, ok := s.databases[database.NormalizeID(dbID)]
+	if !ok {
+		return fmt.Errorf("database %s not found", dbID)
+	}
+
+	for _, p := range pages {
+		if err := checkProperties(db, p.Properties); err != nil {
+			return err
+		}
+
+		s.addPage(db, p)
+	}
+
+	return nil
+}
+
+// AddBlocks appends the blocks to the children of the page or block with the given ID.
+// Blocks without an ID get a random one.
+func (s *Server) AddBlocks(parent notion.UUID, blocks ...notion.Block) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.appendBlocks(parent, blocks)
+}
+
+// AddUsers adds the users to the workspace.
+func (s *Server) AddUsers(users ...notion.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range users {
+		u.Object = "user"
+		s.users = append(s.users, u)
+	}
+}
+
+// Fixture describes the content of a workspace.
+type Fixture struct {
+	Databases []notion.Database `json:"databases"`
+	// Pages are the pages of each database, by database ID.
+	Pages map[notion.UUID]notion.Pages `json:"pages"`
+	// Blocks are the children of each page or block, by its ID.
+	Blocks map[notion.UUID]notion.Blocks `json:"blocks"`
+	Users  notion.Users                  `json:"users"`
+}
+
+// Load adds the content of a JSON encoded fixture.
+func (s *Server) Load(r io.Reader) error {
+	f := Fixture{}
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return fmt.Errorf("decoding fixture: %w", err)
+	}
+
+	for _, db := range f.Databases {
+		s.AddDatabase(db.Id, db.Title.Content(), db.Properties)
+	}
+
+	for dbID, pages := range f.Pages {
+		if err := s.AddPages(dbID, pages...); err != nil {
+			return err
+		}
+	}
+
+	for parent, blocks := range f.Blocks {
+		s.AddBlocks(parent, blocks...)
+	}
+
+	s.AddUsers(f.Users...)
+
+	return nil
+}
+
+// addDatabase adds the database. The lock must be held.
+func (s *Server) addDatabase(db notion.Database) notion.Database {
+	now := time.Now().UTC()
+
+	if db.Id == "" {
+		db.Id = notion.UUID(uuid.NewString())
+	}
+
+	props := db.Properties
+
+	db.Object = "database"
+	db.CreatedTime = &now
+	db.LastEditedTime = now
+	db.Properties = notion.PropertyMetaMap{}
+
+	for name, meta := range props {
+		db.Properties[name] = withName(name, meta)
+	}
+
+	s.databases[database.NormalizeID(db.Id)] = db
+
+	return db
+}
+
+// addPage adds the page to the database. The lock must be held.
+func (s *Server) addPage(db notion.Database, p notion.Page) notion.Page {
+	now := time.Now().UTC()
+
+	if p.Id == "" {
+		p.Id = notion.UUID(uuid.NewString())
+	}
+
+	if p.CreatedTime == nil {
+		p.CreatedTime = &now
+	}
+
+	if p.LastEditedTime.IsZero() {
+		p.LastEditedTime = now
+	}
+
+	p.Object = "page"
+	p.Parent = nil
+	p.Url = "https://www.notion.so/" + database.NormalizeID(p.Id)
+	p.Properties = withSchema(db, notion.PropertyValueMap{}, p.Properties)
+
+	id := database.NormalizeID(p.Id)
+	if _, ok := s.pages[id]; !ok {
+		s.order = append(s.order, id)
+	}
+
+	s.pages[id] = p
+	s.parents[id] = db.Id
+
+	return p
+}
+
+// appendBlocks appends the blocks to the children of the parent. The lock must be held.
+func (s *Server) appendBlocks(parent notion.UUID, blocks notion.Blocks) {
+	now := time.Now().UTC()
+
+	for _, b := range blocks {
+		if b.Id == "" {
+			b.Id = notion.UUID(uuid.NewString())
+		}
+
+		if b.CreatedTime.IsZero() {
+			b.CreatedTime = now
+		}
+
+		if b.LastEditedTime.IsZero() {
+			b.LastEditedTime = now
+		}
+
+		b.Object = "block"
+
+		id := database.NormalizeID(parent)
+		s.blocks[id] = append(s.blocks[id], b)
+	}
+}
+
+// withName returns the property meta with the given name.
+func withName(name string, meta notion.PropertyMeta) notion.PropertyMeta {
+	meta.Name = name
+
+	if meta.Id == "" {
+		meta.Id = name
+	}
+
+	return meta
+}
+
+// withSchema returns the old values updated by the new ones,
+// each with the ID and the type of the property it belongs to.
+// New values may be given by property name or ID.
+func withSchema(db notion.Database, old, values notion.PropertyValueMap) notion.PropertyValueMap {
+	props := notion.PropertyValueMap{}
+
+	for name, v := range old {
+		props[name] = v
+	}
+
+	for key, v := range values {
+		name, meta, _ := lookup(db, key)
+		v.Id, v.Type = meta.Id, meta.Type
+		props[name] = v
+	}
+
+	return props
+}
+
+// withValues returns the old exact numbers or raw values updated by the new ones, by property name.
+// New values may be given by property name or ID.
+func withValues[T any](db notion.Database, old, values map[string]T) map[string]T {
+	res := map[string]T{}
+
+	for name, v := range old {
+		res[name] = v
+	}
+
+	for key, v := range values {
+		if name, _, ok := lookup(db, key); ok {
+			res[name] = v
+		}
+	}
+
+	return res
+}
+
+// lookup returns the property of the database with the given name or ID.
+func lookup(db notion.Database, key string) (string, notion.PropertyMeta, bool) {
+	if meta, ok := db.Properties[key]; ok {
+		return key, meta, true
+	}
+
+	for name, meta := range db.Properties {
+		if meta.Id == key {
+			return name, meta, true
+		}
+	}
+
+	return "", notion.PropertyMeta{}, false
+}
+
+// checkProperties checks that all values belong to a property of the database.
+func checkProperties(db notion.Database, values notion.PropertyValueMap) error {
+	for key, v := range values {
+		_, meta, ok := lookup(db, key)
+		if !ok {
+			return fmt.Errorf("%s is not a property that exists", key)
+		}
+
+		if v.Type != "" && v.Type != meta.Type {
+			return fmt.Errorf("%s is expected to be %s", key, meta.Type)
+		}
+	}
+
+	return nil
+}
+
+// checkFilter checks that the filter is valid and only refers to properties of the database.
+func checkFilter(db notion.Database, f query.Filter) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	for _, key := range f.Properties() {
+		if _, _, ok := lookup(db, key); !ok {
+			return fmt.Errorf("could not find property with name or id: %s", key)
+		}
+	}
+
+	return nil
+}
+
+// paginate returns the items starting at the cursor, at most size of them,
+// and the cursor of the item after them, if any.
+func paginate[T any](items []T, id func(T) notion.UUID, cursor string, size int) ([]T, string, error) {
+	if size <= 0 || size > maxPageSize {
+		size = maxPageSize
+	}
+
+	start := 0
+
+	if cursor != "" {
+		start = -1
+
+		for i, item := range items {
+			if database.NormalizeID(id(item)) == database.NormalizeID(notion.UUID(cursor)) {
+				start = i
+				break
+			}
+		}
+
+		if start < 0 {
+			return nil, "", fmt.Errorf("start_cursor %s is invalid", cursor)
+		}
+	}
+
+	end := start + size
+	if end >= len(items) {
+		return items[start:], "", nil
+	}
+
+	return items[start:end], string(id(items[end])), nil
+}
+
+// pageSize returns the page size given in the query string.
+func pageSize(r *http.Request) int {
+	n, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+	return n
+}
+
+// queryPages returns the pages of the database that are not archived and match the query.
+// The lock must be held.
+func (s *Server) queryPages(db notion.Database, q databaseQuery) []database.Page {
+	pages := []database.Page{}
+
+	for _, id := range s.order {
+		p := s.pages[id]
+		if !p.Archived && database.NormalizeID(s.parents[id]) == database.NormalizeID(db.Id) {
+			pages = append(pages, s.page(p).Page)
+		}
+	}
+
+	return query.EvaluateExact(pages, q.Filter, q.Sorts,
+		query.WithSchema(db.Properties), query.WithStatuses(s.statuses[database.NormalizeID(db.Id)]))
+}
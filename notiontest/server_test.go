@@ -0,0 +1,226 @@
+package notiontest_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion-codegen/notiontest"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	dbID   = "4a5b6c7d-0000-4000-8000-000000000001"
+	pageID = "4a5b6c7d-0000-4000-8000-000000000002"
+	userID = "4a5b6c7d-0000-4000-8000-000000000003"
+)
+
+const fixture = `{
+	"databases": [{
+		"id": "` + dbID + `",
+		"title": [{"type": "text", "text": {"content": "Tasks"}, "plain_text": "Tasks"}],
+		"properties": {
+			"Name": {"id": "title", "type": "title", "title": {}},
+			"Done": {"type": "checkbox", "checkbox": {}}
+		}
+	}],
+	"pages": {
+		"` + dbID + `": [{
+			"id": "` + pageID + `",
+			"properties": {
+				"Name": {"title": [{"type": "text", "text": {"content": "Write tests"}, "plain_text": "Write tests"}]},
+				"Done": {"checkbox": true}
+			}
+		}]
+	},
+	"blocks": {
+		"` + pageID + `": [{"type": "divider", "divider": {}}]
+	},
+	"users": [{"id": "` + userID + `", "type": "person", "name": "Ada"}]
+}`
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	require.NoError(t, srv.Load(strings.NewReader(fixture)))
+
+	cli, err := srv.Client()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	db, err := cli.GetNotionDatabase(ctx, dbID)
+	require.NoError(t, err)
+	assert.Equal(t, "Tasks", db.Title.Content())
+	assert.Equal(t, "Done", db.Properties["Done"].Name)
+
+	p, err := cli.GetNotionPage(ctx, pageID)
+	require.NoError(t, err)
+	assert.Equal(t, "Write tests", p.Properties["Name"].GetTitle().Content())
+	assert.Equal(t, notion.PropertyTypeCheckbox, p.Properties["Done"].Type)
+
+	resp, err := cli.GetPage(ctx, pageID)
+	require.NoError(t, err)
+	assert.Contains(t, string(resp.Body), `"database_id":"`+dbID+`"`)
+
+	// create a page and find it
+	name := notion.NewRichTexts("Ship it")
+	done := false
+
+	created, err := cli.CreatePageWithBody(ctx, "application/json", strings.NewReader(`{
+		"parent": {"database_id": "`+dbID+`"},
+		"properties": {"Name": {"title": [{"type": "text", "text": {"content": "Ship it"}}]}}
+	}`))
+	require.NoError(t, err)
+	require.NotNil(t, created.JSON200)
+	assert.Equal(t, name.Content(), created.JSON200.Properties["Name"].GetTitle().Content())
+
+	doneFilter := "Done"
+	pages, err := cli.GetDatabaseEntries(ctx, dbID,
+		&notion.Filter{Property: &doneFilter, Checkbox: &notion.CheckboxFilter{Equals: done}}, nil)
+	require.NoError(t, err)
+	require.Len(t, pages, 1)
+	assert.Equal(t, created.JSON200.Id, pages[0].Id)
+
+	// archive it
+	archived, err := cli.UpdatePageWithBody(ctx, notion.Id(pages[0].Id), "application/json",
+		strings.NewReader(`{"archived": true}`))
+	require.NoError(t, err)
+	assert.True(t, archived.JSON200.Archived)
+
+	pages, err = cli.GetAllDatabaseEntries(ctx, dbID)
+	require.NoError(t, err)
+	assert.Len(t, pages, 1)
+
+	// blocks
+	blocks, err := cli.GetAllBlocks(ctx, pageID)
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+	assert.Equal(t, notion.BlockTypeDivider, blocks[0].Type)
+
+	// users
+	u, err := cli.GetUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", u.JSON200.Name)
+
+	me, err := cli.GetMe(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, notion.UserTypeBot, me.JSON200.Type)
+}
+
+func TestServer_Errors(t *testing.T) {
+	t.Parallel()
+
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	srv.AddDatabase(dbID, "Tasks", notion.PropertyMetaMap{"Name": notion.TitleProperty})
+
+	cli, err := srv.Client()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = cli.GetNotionPage(ctx, "unknown")
+
+	var notionErr *notion.Error
+	require.ErrorAs(t, err, &notionErr)
+	assert.Equal(t, http.StatusNotFound, notionErr.Status)
+
+	resp, err := cli.CreatePageWithBody(ctx, "application/json", strings.NewReader(`{
+		"parent": {"database_id": "`+dbID+`"},
+		"properties": {"Unknown": {"checkbox": true}}
+	}`))
+	require.NoError(t, err)
+	require.NotNil(t, resp.JSON400)
+	assert.Equal(t, "validation_error", resp.JSON400.Code)
+
+	unknown := "Unknown"
+	_, err = cli.GetDatabaseEntries(ctx, dbID, &notion.Filter{Property: &unknown, Contains: &unknown}, nil)
+	require.ErrorAs(t, err, &notionErr)
+	assert.Equal(t, http.StatusBadRequest, notionErr.Status)
+
+	queryResp, err := cli.QueryDatabaseWithBody(ctx, dbID, "application/json",
+		strings.NewReader(`{"filter": {"property": "Name", "status": {"equals": "Done"}}}`))
+	require.NoError(t, err)
+	require.NotNil(t, queryResp.JSON400)
+	assert.Equal(t, `body failed to parse: filter condition "status" is not supported`, queryResp.JSON400.Message)
+}
+
+func TestServer_Pagination(t *testing.T) {
+	t.Parallel()
+
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	srv.AddDatabase(dbID, "Tasks", notion.PropertyMetaMap{"Name": notion.TitleProperty})
+
+	pages := make(notion.Pages, 250)
+	require.NoError(t, srv.AddPages(dbID, pages...))
+
+	cli, err := srv.Client()
+	require.NoError(t, err)
+
+	got, err := cli.GetAllDatabaseEntries(context.Background(), dbID)
+	require.NoError(t, err)
+	assert.Len(t, got, 250)
+}
+
+func TestServer_Dates(t *testing.T) {
+	t.Parallel()
+
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	db := srv.AddDatabase("", "Events", notion.PropertyMetaMap{
+		"Name": {Id: "title", Type: notion.PropertyTypeTitle},
+		"When": {Type: notion.PropertyTypeDate},
+	})
+
+	cli, err := srv.Client()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	holidays := database.DateValue{
+		Start:  time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC),
+		End:    time.Date(2022, 12, 26, 0, 0, 0, 0, time.UTC),
+		AllDay: true,
+	}
+
+	p, err := database.CreatePage(ctx, cli, notion.Id(db.Id), notion.PropertyValueMap{
+		"When": {Type: notion.PropertyTypeDate, Date: holidays.Date()},
+	})
+	require.NoError(t, err)
+
+	resp, err := cli.GetPage(ctx, notion.Id(p.Id))
+	require.Error(t, err, "notion.Page can't decode all-day dates")
+	assert.Nil(t, resp)
+
+	p, err = database.GetPage(ctx, cli, notion.Id(p.Id))
+	require.NoError(t, err)
+	assert.Equal(t, holidays, database.NewDateValue(p.Properties["When"].GetDate()))
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	party := database.DateValue{Start: time.Date(2022, 12, 31, 20, 0, 0, 0, berlin), TimeZone: "Europe/Berlin"}
+
+	p, err = database.UpdatePage(ctx, cli, notion.Id(p.Id), notion.PropertyValueMap{
+		"When": {Type: notion.PropertyTypeDate, Date: party.Date()},
+	})
+	require.NoError(t, err)
+
+	got := database.NewDateValue(p.Properties["When"].GetDate())
+	assert.False(t, got.IsAllDay())
+	assert.True(t, got.Start.Equal(party.Start))
+	assert.Equal(t, "Europe/Berlin", got.TimeZone)
+}
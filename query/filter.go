@@ -0,0 +1,618 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+const (
+	// TimestampCreatedTime filters or sorts by the time a page was created.
+	TimestampCreatedTime = "created_time"
+	// TimestampLastEditedTime filters or sorts by the time a page was last edited.
+	TimestampLastEditedTime = "last_edited_time"
+)
+
+// Filter is a filter of a database query, encoded the way Notion's API expects it.
+//
+// Unlike notion.Filter, it covers the conditions of most property types.
+// Conditions on statuses, people, formulas, rollups and unique IDs can't be evaluated locally,
+// so decoding a filter with them fails.
+// Only one of the conditions should be set.
+type Filter struct {
+	And []Filter `json:"and,omitempty"`
+	Or  []Filter `json:"or,omitempty"`
+
+	// Property is the name or ID of the property the condition applies to.
+	Property string `json:"property,omitempty"`
+	// Timestamp is set instead of the property to filter by a timestamp of the page.
+	Timestamp string `json:"timestamp,omitempty"`
+
+	Title       *TextCondition        `json:"title,omitempty"`
+	RichText    *TextCondition        `json:"rich_text,omitempty"`
+	Number      *NumberCondition      `json:"number,omitempty"`
+	Checkbox    *CheckboxCondition    `json:"checkbox,omitempty"`
+	Select      *SelectCondition      `json:"select,omitempty"`
+	MultiSelect *MultiSelectCondition `json:"multi_select,omitempty"`
+	Date        *DateCondition        `json:"date,omitempty"`
+	Relation    *RelationCondition    `json:"relation,omitempty"`
+	Files       *EmptyCondition       `json:"files,omitempty"`
+
+	CreatedTime    *DateCondition `json:"created_time,omitempty"`
+	LastEditedTime *DateCondition `json:"last_edited_time,omitempty"`
+}
+
+// conditions are the keys of all filter conditions that can be evaluated.
+var conditions = map[string]bool{
+	"and": true, "or": true, "property": true, "timestamp": true,
+	"title": true, "rich_text": true, "number": true, "checkbox": true, "select": true, "multi_select": true,
+	"date": true, "relation": true, "files": true, "created_time": true, "last_edited_time": true,
+}
+
+// UnmarshalJSON decodes the filter and returns an error for conditions that can't be evaluated,
+// so that they don't go unnoticed.
+func (f *Filter) UnmarshalJSON(b []byte) error {
+	keys := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &keys); err != nil {
+		return err
+	}
+
+	for key := range keys {
+		if !conditions[key] {
+			return fmt.Errorf("filter condition %q is not supported", key)
+		}
+	}
+
+	type filter Filter
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+
+	return d.Decode((*filter)(f))
+}
+
+// Validate returns an error if the filter or any of its sub-filters has no condition
+// or a condition that doesn't fit the timestamp.
+func (f Filter) Validate() error {
+	switch {
+	case f.And != nil:
+		return validateAll(f.And)
+	case f.Or != nil:
+		return validateAll(f.Or)
+	case f.Timestamp == TimestampCreatedTime && f.CreatedTime != nil,
+		f.Timestamp == TimestampLastEditedTime && f.LastEditedTime != nil:
+		return nil
+	case f.Timestamp != "":
+		return fmt.Errorf("filter by timestamp %q has no condition for it", f.Timestamp)
+	case f.Property == "":
+		return nil
+	case f.condition():
+		return nil
+	default:
+		return fmt.Errorf("filter of property %q has no condition", f.Property)
+	}
+}
+
+func validateAll(filters []Filter) error {
+	for _, sub := range filters {
+		if err := sub.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// condition reports whether the filter has a condition for its property.
+func (f Filter) condition() bool {
+	return f.Title != nil || f.RichText != nil || f.Number != nil || f.Checkbox != nil ||
+		f.Select != nil || f.MultiSelect != nil || f.Date != nil || f.Relation != nil || f.Files != nil
+}
+
+// EmptyCondition checks whether a value is empty.
+type EmptyCondition struct {
+	IsEmpty    bool `json:"is_empty,omitempty"`
+	IsNotEmpty bool `json:"is_not_empty,omitempty"`
+}
+
+// TextCondition is a condition for titles and rich texts.
+// All comparisons are case-insensitive.
+type TextCondition struct {
+	Equals         *string `json:"equals,omitempty"`
+	DoesNotEqual   *string `json:"does_not_equal,omitempty"`
+	Contains       *string `json:"contains,omitempty"`
+	DoesNotContain *string `json:"does_not_contain,omitempty"`
+	StartsWith     *string `json:"starts_with,omitempty"`
+	EndsWith       *string `json:"ends_with,omitempty"`
+	EmptyCondition
+}
+
+// NumberCondition is a condition for numbers.
+type NumberCondition struct {
+	Equals               *float64 `json:"equals,omitempty"`
+	DoesNotEqual         *float64 `json:"does_not_equal,omitempty"`
+	GreaterThan          *float64 `json:"greater_than,omitempty"`
+	LessThan             *float64 `json:"less_than,omitempty"`
+	GreaterThanOrEqualTo *float64 `json:"greater_than_or_equal_to,omitempty"`
+	LessThanOrEqualTo    *float64 `json:"less_than_or_equal_to,omitempty"`
+	EmptyCondition
+}
+
+// CheckboxCondition is a condition for checkboxes.
+type CheckboxCondition struct {
+	Equals       *bool `json:"equals,omitempty"`
+	DoesNotEqual *bool `json:"does_not_equal,omitempty"`
+}
+
+// SelectCondition is a condition for selects, compared by the name of the option.
+type SelectCondition struct {
+	Equals       *string `json:"equals,omitempty"`
+	DoesNotEqual *string `json:"does_not_equal,omitempty"`
+	EmptyCondition
+}
+
+// MultiSelectCondition is a condition for multi-selects, compared by the names of the options.
+type MultiSelectCondition struct {
+	Contains       *string `json:"contains,omitempty"`
+	DoesNotContain *string `json:"does_not_contain,omitempty"`
+	EmptyCondition
+}
+
+// RelationCondition is a condition for relations, compared by the IDs of the related pages.
+type RelationCondition struct {
+	Contains       *notion.UUID `json:"contains,omitempty"`
+	DoesNotContain *notion.UUID `json:"does_not_contain,omitempty"`
+	EmptyCondition
+}
+
+// DateCondition is a condition for dates, compared by the start of the date.
+//
+// Dates are given as in ISO 8601, either with time or without.
+// A date without time is compared with the day of the value.
+// Relative conditions are relative to the current time.
+type DateCondition struct {
+	Equals     *string `json:"equals,omitempty"`
+	Before     *string `json:"before,omitempty"`
+	After      *string `json:"after,omitempty"`
+	OnOrBefore *string `json:"on_or_before,omitempty"`
+	OnOrAfter  *string `json:"on_or_after,omitempty"`
+
+	PastWeek  *struct{} `json:"past_week,omitempty"`
+	PastMonth *struct{} `json:"past_month,omitempty"`
+	PastYear  *struct{} `json:"past_year,omitempty"`
+	NextWeek  *struct{} `json:"next_week,omitempty"`
+	NextMonth *struct{} `json:"next_month,omitempty"`
+	NextYear  *struct{} `json:"next_year,omitempty"`
+	EmptyCondition
+}
+
+// NewFilter returns the filter equivalent to the notion filter.
+func NewFilter(f notion.Filter) Filter {
+	res := Filter{}
+
+	if f.And != nil {
+		for _, sub := range *f.And {
+			res.And = append(res.And, NewFilter(sub))
+		}
+	}
+
+	if f.Or != nil {
+		for _, sub := range *f.Or {
+			res.Or = append(res.Or, NewFilter(sub))
+		}
+	}
+
+	if f.Property != nil {
+		res.Property = *f.Property
+	}
+
+	switch {
+	case f.Checkbox != nil:
+		res.Checkbox = &CheckboxCondition{Equals: &f.Checkbox.Equals}
+	case f.RichText != nil:
+		res.RichText = &TextCondition{Contains: &f.RichText.Contains}
+	case f.Contains != nil:
+		res.RichText = &TextCondition{Contains: f.Contains}
+	}
+
+	return res
+}
+
+// Properties returns the names or IDs of all properties the filter refers to.
+func (f Filter) Properties() []string {
+	props := []string{}
+
+	if f.Property != "" {
+		props = append(props, f.Property)
+	}
+
+	for _, sub := range f.And {
+		props = append(props, sub.Properties()...)
+	}
+
+	for _, sub := range f.Or {
+		props = append(props, sub.Properties()...)
+	}
+
+	return props
+}
+
+// Match reports whether the page matches the filter.
+// A property is looked up by its name and, failing that, by its ID.
+// A filter that is not valid, see Validate, matches no page.
+func (f Filter) Match(p notion.Page) bool {
+	return f.MatchExact(database.Page{Page: p})
+}
+
+// MatchExact is like Match, but compares the exact numbers of the page.
+func (f Filter) MatchExact(p database.Page) bool {
+	switch {
+	case f.And != nil:
+		for _, sub := range f.And {
+			if !sub.MatchExact(p) {
+				return false
+			}
+		}
+
+		return true
+	case f.Or != nil:
+		for _, sub := range f.Or {
+			if sub.MatchExact(p) {
+				return true
+			}
+		}
+
+		return false
+	case f.Timestamp != "":
+		return f.matchTimestamp(p.Page)
+	case f.Property == "":
+		return true
+	}
+
+	v := property(p, f.Property)
+
+	switch {
+	case f.Title != nil:
+		return f.Title.match(text(v))
+	case f.RichText != nil:
+		return f.RichText.match(text(v))
+	case f.Number != nil:
+		return f.Number.match(v)
+	case f.Checkbox != nil:
+		return f.Checkbox.match(v.GetCheckbox())
+	case f.Select != nil:
+		return f.Select.match(v.Select)
+	case f.MultiSelect != nil:
+		return f.MultiSelect.match(v.GetMultiSelect().GetNames())
+	case f.Date != nil:
+		return f.Date.match(v.Date)
+	case f.Relation != nil:
+		return f.Relation.match(v.GetRelation())
+	case f.Files != nil:
+		return f.Files.match(len(v.GetFiles()) == 0)
+	default:
+		return false
+	}
+}
+
+func (f Filter) matchTimestamp(p notion.Page) bool {
+	switch {
+	case f.Timestamp == TimestampCreatedTime && f.CreatedTime != nil:
+		if p.CreatedTime == nil {
+			return f.CreatedTime.match(nil)
+		}
+
+		return f.CreatedTime.match(&notion.Date{Start: *p.CreatedTime})
+	case f.Timestamp == TimestampLastEditedTime && f.LastEditedTime != nil:
+		return f.LastEditedTime.match(&notion.Date{Start: p.LastEditedTime})
+	default:
+		return false
+	}
+}
+
+// property returns the value of the property with the given name or ID,
+// together with its exact number and its raw value.
+func property(p database.Page, key string) database.Value {
+	name := key
+
+	v, ok := p.Properties[key]
+	if !ok {
+		for n, pv := range p.Properties {
+			if pv.Id == key {
+				name, v = n, pv
+				break
+			}
+		}
+	}
+
+	return resolve(database.Value{PropertyValue: v, Exact: p.Numbers[name], Raw: p.Raw[name]})
+}
+
+// resolve sets the property value to the equivalent of its raw value, if it has one,
+// so that statuses, formulas, rollups and people can be compared like other values.
+func resolve(v database.Value) database.Value {
+	if len(v.Raw) == 0 {
+		return v
+	}
+
+	switch v.Type {
+	case database.PropertyTypeStatus:
+		if s := database.NewStatus(v.Raw); s.Name != "" {
+			v.Select = &s
+		}
+	case notion.PropertyTypeFormula:
+		switch f := database.NewFormula(v.Raw); f.Type {
+		case database.FormulaString:
+			v.RichText = database.Text(f.String)
+		case database.FormulaNumber:
+			v.Number, v.Exact = database.Number(f.Number), database.Float64Number(f.Number)
+		case database.FormulaBoolean:
+			v.Checkbox = &f.Boolean
+		case database.FormulaDate:
+			v.Date = f.Date.Date()
+		}
+	case notion.PropertyTypeRollup:
+		switch r := database.NewRollup(v.Raw); r.Type {
+		case database.RollupNumber:
+			v.Number, v.Exact = database.Number(r.Number), database.Float64Number(r.Number)
+		case database.RollupDate:
+			v.Date = r.Date.Date()
+		}
+	case notion.PropertyTypePeople:
+		names := []string{}
+		for _, u := range database.NewUsers(v.Raw) {
+			names = append(names, u.Name)
+		}
+
+		v.RichText = database.Text(strings.Join(names, ","))
+	}
+
+	return v
+}
+
+// number returns the exact number of the value, or nil if it has none.
+func number(v database.Value) *float64 {
+	if v.Number == nil && v.Exact == "" {
+		return nil
+	}
+
+	n := database.Float64(v.PropertyValue, v.Exact)
+
+	return &n
+}
+
+// text returns the textual content of a property value.
+func text(v database.Value) string {
+	switch {
+	case v.Title != nil:
+		return v.Title.Content()
+	case v.RichText != nil:
+		return v.RichText.Content()
+	case v.Select != nil:
+		return v.Select.Name
+	case v.MultiSelect != nil:
+		return strings.Join(v.MultiSelect.GetNames(), ",")
+	default:
+		return ""
+	}
+}
+
+// match reports whether the value is empty or not, as demanded.
+func (c EmptyCondition) match(empty bool) bool {
+	switch {
+	case c.IsEmpty:
+		return empty
+	case c.IsNotEmpty:
+		return !empty
+	default:
+		return true
+	}
+}
+
+func (c EmptyCondition) isSet() bool { return c.IsEmpty || c.IsNotEmpty }
+
+func (c TextCondition) match(s string) bool {
+	if c.isSet() {
+		return c.EmptyCondition.match(s == "")
+	}
+
+	s = strings.ToLower(s)
+	lower := func(s *string) string { return strings.ToLower(*s) }
+
+	switch {
+	case c.Equals != nil:
+		return s == lower(c.Equals)
+	case c.DoesNotEqual != nil:
+		return s != lower(c.DoesNotEqual)
+	case c.Contains != nil:
+		return strings.Contains(s, lower(c.Contains))
+	case c.DoesNotContain != nil:
+		return !strings.Contains(s, lower(c.DoesNotContain))
+	case c.StartsWith != nil:
+		return s != "" && strings.HasPrefix(s, lower(c.StartsWith))
+	case c.EndsWith != nil:
+		return s != "" && strings.HasSuffix(s, lower(c.EndsWith))
+	default:
+		return true
+	}
+}
+
+func (c NumberCondition) match(value database.Value) bool {
+	n := number(value)
+
+	if c.isSet() {
+		return c.EmptyCondition.match(n == nil)
+	}
+
+	if n == nil {
+		// an empty number is only different from any number
+		return c.DoesNotEqual != nil
+	}
+
+	// without the exact number, the filter is compared with the precision of the value
+	v := *n
+	with := func(f *float64) float64 { return *f }
+
+	if value.Exact == "" {
+		with = func(f *float64) float64 { return float64(float32(*f)) }
+	}
+
+	switch {
+	case c.Equals != nil:
+		return v == with(c.Equals)
+	case c.DoesNotEqual != nil:
+		return v != with(c.DoesNotEqual)
+	case c.GreaterThan != nil:
+		return v > with(c.GreaterThan)
+	case c.LessThan != nil:
+		return v < with(c.LessThan)
+	case c.GreaterThanOrEqualTo != nil:
+		return v >= with(c.GreaterThanOrEqualTo)
+	case c.LessThanOrEqualTo != nil:
+		return v <= with(c.LessThanOrEqualTo)
+	default:
+		return true
+	}
+}
+
+func (c CheckboxCondition) match(b bool) bool {
+	switch {
+	case c.Equals != nil:
+		return b == *c.Equals
+	case c.DoesNotEqual != nil:
+		return b != *c.DoesNotEqual
+	default:
+		return true
+	}
+}
+
+func (c SelectCondition) match(v *notion.SelectValue) bool {
+	name := ""
+	if v != nil {
+		name = v.Name
+	}
+
+	if c.isSet() {
+		return c.EmptyCondition.match(name == "")
+	}
+
+	switch {
+	case c.Equals != nil:
+		return name == *c.Equals
+	case c.DoesNotEqual != nil:
+		return name != *c.DoesNotEqual
+	default:
+		return true
+	}
+}
+
+func (c MultiSelectCondition) match(names []string) bool {
+	if c.isSet() {
+		return c.EmptyCondition.match(len(names) == 0)
+	}
+
+	has := func(name string) bool {
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	switch {
+	case c.Contains != nil:
+		return has(*c.Contains)
+	case c.DoesNotContain != nil:
+		return !has(*c.DoesNotContain)
+	default:
+		return true
+	}
+}
+
+func (c RelationCondition) match(refs notion.References) bool {
+	if c.isSet() {
+		return c.EmptyCondition.match(len(refs) == 0)
+	}
+
+	has := func(id notion.UUID) bool {
+		for _, ref := range refs {
+			if database.NormalizeID(ref.Id) == database.NormalizeID(id) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	switch {
+	case c.Contains != nil:
+		return has(*c.Contains)
+	case c.DoesNotContain != nil:
+		return !has(*c.DoesNotContain)
+	default:
+		return true
+	}
+}
+
+func (c DateCondition) match(d *notion.Date) bool {
+	if c.isSet() {
+		return c.EmptyCondition.match(d == nil || d.Start.IsZero())
+	}
+
+	if d == nil || d.Start.IsZero() {
+		return false
+	}
+
+	now := time.Now()
+	within := func(from, to time.Time) bool {
+		return !d.Start.Before(from) && !d.Start.After(to)
+	}
+
+	switch {
+	case c.Equals != nil:
+		return compareDate(d.Start, *c.Equals) == 0
+	case c.Before != nil:
+		return compareDate(d.Start, *c.Before) < 0
+	case c.After != nil:
+		return compareDate(d.Start, *c.After) > 0
+	case c.OnOrBefore != nil:
+		return compareDate(d.Start, *c.OnOrBefore) <= 0
+	case c.OnOrAfter != nil:
+		return compareDate(d.Start, *c.OnOrAfter) >= 0
+	case c.PastWeek != nil:
+		return within(now.AddDate(0, 0, -7), now)
+	case c.PastMonth != nil:
+		return within(now.AddDate(0, -1, 0), now)
+	case c.PastYear != nil:
+		return within(now.AddDate(-1, 0, 0), now)
+	case c.NextWeek != nil:
+		return within(now, now.AddDate(0, 0, 7))
+	case c.NextMonth != nil:
+		return within(now, now.AddDate(0, 1, 0))
+	case c.NextYear != nil:
+		return within(now, now.AddDate(1, 0, 0))
+	default:
+		return true
+	}
+}
+
+// compareDate compares the time with the date given in ISO 8601.
+// If the date has no time, only the day of the time is compared.
+// An invalid date is treated like the zero time.
+func compareDate(t time.Time, date string) int {
+	if day, err := time.Parse("2006-01-02", date); err == nil {
+		y, m, d := t.Date()
+		return compareOrdered(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix(), day.Unix())
+	}
+
+	other, _ := time.Parse(time.RFC3339, date)
+
+	return compareOrdered(t.UnixNano(), other.UnixNano())
+}
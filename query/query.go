@@ -0,0 +1,88 @@
+// Package query evaluates database queries locally.
+//
+// Filters and sorts follow the semantics Notion documents for its API:
+//   - text conditions are case-insensitive
+//   - an unchecked checkbox is false
+//   - empty values only match conditions that expect them to be empty or different from something
+//   - empty values are sorted last, whatever the direction
+//   - selects and statuses are sorted by the order of their options, if it is given, see WithSchema
+package query
+
+import (
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// Apply returns the pages that match the filter, in the order given by the sorts.
+// A filter or sorts that are nil are ignored.
+func Apply(pages notion.Pages, f *notion.Filter, sorts *notion.Sorts) notion.Pages {
+	return pagesOf(ApplyExact(exactPages(pages), f, sorts))
+}
+
+// ApplyExact is like Apply, but compares the exact numbers and the raw values of the pages
+// and sorts as the options say.
+func ApplyExact(pages []database.Page, f *notion.Filter, sorts *notion.Sorts, opts ...Option) []database.Page {
+	var filter *Filter
+
+	if f != nil {
+		conv := NewFilter(*f)
+		filter = &conv
+	}
+
+	if sorts == nil {
+		return EvaluateExact(pages, filter, nil)
+	}
+
+	return EvaluateExact(pages, filter, NewSorts(*sorts), opts...)
+}
+
+// Evaluate returns the pages that match the filter, in the order given by the sorts.
+// A filter that is nil is ignored.
+func Evaluate(pages notion.Pages, f *Filter, sorts Sorts) notion.Pages {
+	return pagesOf(EvaluateExact(exactPages(pages), f, sorts))
+}
+
+// EvaluateExact is like Evaluate, but compares the exact numbers and the raw values of the pages
+// and sorts as the options say.
+func EvaluateExact(pages []database.Page, f *Filter, sorts Sorts, opts ...Option) []database.Page {
+	res := []database.Page{}
+
+	for _, p := range pages {
+		if f == nil || f.MatchExact(p) {
+			res = append(res, p)
+		}
+	}
+
+	sorts.SortExact(res, opts...)
+
+	return res
+}
+
+func exactPages(pages notion.Pages) []database.Page {
+	res := make([]database.Page, len(pages))
+	for i, p := range pages {
+		res[i] = database.Page{Page: p}
+	}
+
+	return res
+}
+
+func pagesOf(pages []database.Page) notion.Pages {
+	res := make(notion.Pages, len(pages))
+	for i, p := range pages {
+		res[i] = p.Page
+	}
+
+	return res
+}
+
+// Match reports whether the page matches the filter.
+func Match(f notion.Filter, p notion.Page) bool {
+	return NewFilter(f).Match(p)
+}
+
+// Sort sorts the pages in the order given by the sorts.
+// The first sort takes precedence, the others are used if the values are equal.
+func Sort(pages notion.Pages, sorts notion.Sorts) {
+	NewSorts(sorts).Sort(pages)
+}
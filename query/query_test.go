@@ -0,0 +1,362 @@
+package query_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion-codegen/query"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPage(id notion.UUID, name string, done bool, n float32) notion.Page {
+	title := notion.NewRichTexts(name)
+
+	return notion.Page{
+		Id: id,
+		Properties: notion.PropertyValueMap{
+			"Name":  {Title: &title},
+			"Done":  {Checkbox: &done},
+			"Count": {Number: &n},
+		},
+	}
+}
+
+func ids(pages notion.Pages) []notion.UUID {
+	ids := make([]notion.UUID, len(pages))
+	for i, p := range pages {
+		ids[i] = p.Id
+	}
+
+	return ids
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	pages := notion.Pages{
+		newPage("a", "apple", true, 3),
+		newPage("b", "banana", false, 1),
+		newPage("c", "cherry", true, 2),
+		newPage("d", "date", false, 2),
+	}
+
+	name, done := "Name", "Done"
+	an := "an"
+
+	assert.Equal(t, []notion.UUID{"a", "b", "c", "d"}, ids(query.Apply(pages, nil, nil)))
+
+	assert.Equal(t, []notion.UUID{"a", "c"}, ids(query.Apply(pages, &notion.Filter{
+		Property: &done,
+		Checkbox: &notion.CheckboxFilter{Equals: true},
+	}, nil)))
+
+	assert.Equal(t, []notion.UUID{"a", "b"}, ids(query.Apply(pages, &notion.Filter{Or: &notion.Filters{
+		{Property: &name, Contains: &an},
+		{And: &notion.Filters{
+			{Property: &done, Checkbox: &notion.CheckboxFilter{Equals: true}},
+			{Property: &name, RichText: &notion.TextFilter{Contains: "pp"}},
+		}},
+	}}, nil)))
+
+	assert.Equal(t, []notion.UUID{"a", "d", "c", "b"}, ids(query.Apply(pages, nil, &notion.Sorts{
+		{Property: "Count", Direction: notion.SortDirectionDescending},
+		{Property: "Done", Direction: notion.SortDirectionAscending},
+	})))
+}
+
+func conformancePages() notion.Pages {
+	apple, banana, cherry := notion.NewRichTexts("Apple"), notion.NewRichTexts("banana"), notion.NewRichTexts("Cherry pie")
+	empty := notion.RichTexts{}
+	yes, no := true, false
+	three, one := float32(3), float32(1)
+	labels := notion.PropertyOptions{{Name: "x"}, {Name: "y"}}
+	label := notion.PropertyOptions{{Name: "y"}}
+	created := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	return notion.Pages{
+		{
+			Id:             "a",
+			CreatedTime:    &created,
+			LastEditedTime: created.AddDate(0, 0, 2),
+			Properties: notion.PropertyValueMap{
+				"Name":   {Id: "title", Title: &apple},
+				"Notes":  {RichText: &empty},
+				"Count":  {Number: &three},
+				"Done":   {Checkbox: &yes},
+				"Tag":    {Select: &notion.SelectValue{Name: "Red"}},
+				"Labels": {MultiSelect: &labels},
+				"Due":    {Date: &notion.Date{Start: time.Date(2022, 5, 10, 0, 0, 0, 0, time.UTC)}},
+				"Rel":    {Relation: &notion.References{{Id: "5f3a9c8e-1d2b-4c6a-9e7f-0a1b2c3d4e5f"}}},
+				"Files":  {Files: &notion.Files{{Type: notion.FileTypeExternal}}},
+			},
+		},
+		{
+			Id:             "b",
+			CreatedTime:    &created,
+			LastEditedTime: created.AddDate(0, 0, 1),
+			Properties: notion.PropertyValueMap{
+				"Name":   {Id: "title", Title: &banana},
+				"Notes":  {RichText: &apple},
+				"Count":  {Number: &one},
+				"Done":   {Checkbox: &no},
+				"Tag":    {Select: &notion.SelectValue{Name: "Yellow"}},
+				"Labels": {MultiSelect: &label},
+				"Due":    {Date: &notion.Date{Start: time.Date(2022, 5, 11, 15, 0, 0, 0, time.UTC)}},
+				"Rel":    {Relation: &notion.References{}},
+			},
+		},
+		{
+			Id:             "c",
+			LastEditedTime: created,
+			Properties: notion.PropertyValueMap{
+				"Name": {Id: "title", Title: &cherry},
+			},
+		},
+	}
+}
+
+// TestConformance checks the evaluation of filters and sorts in Notion's JSON encoding.
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name   string
+		filter string
+		sorts  string
+		want   []notion.UUID
+	}{
+		{"no filter", `{}`, ``, []notion.UUID{"a", "b", "c"}},
+		{"property by ID", `{"property": "title", "title": {"equals": "apple"}}`, ``, []notion.UUID{"a"}},
+		{"unknown property", `{"property": "Unknown", "rich_text": {"is_empty": true}}`, ``, []notion.UUID{"a", "b", "c"}},
+
+		// text
+		{"text equals is case-insensitive", `{"property": "Name", "title": {"equals": "BANANA"}}`, ``, []notion.UUID{"b"}},
+		{"text does not equal", `{"property": "Name", "title": {"does_not_equal": "banana"}}`, ``, []notion.UUID{"a", "c"}},
+		{"text contains is case-insensitive", `{"property": "Name", "title": {"contains": "PIE"}}`, ``, []notion.UUID{"c"}},
+		{"text does not contain matches empty", `{"property": "Notes", "rich_text": {"does_not_contain": "app"}}`, ``, []notion.UUID{"a", "c"}},
+		{"text starts with", `{"property": "Name", "title": {"starts_with": "ch"}}`, ``, []notion.UUID{"c"}},
+		{"text ends with", `{"property": "Name", "title": {"ends_with": "NA"}}`, ``, []notion.UUID{"b"}},
+		{"text is empty", `{"property": "Notes", "rich_text": {"is_empty": true}}`, ``, []notion.UUID{"a", "c"}},
+		{"text is not empty", `{"property": "Notes", "rich_text": {"is_not_empty": true}}`, ``, []notion.UUID{"b"}},
+
+		// number
+		{"number equals", `{"property": "Count", "number": {"equals": 3}}`, ``, []notion.UUID{"a"}},
+		{"number does not equal matches empty", `{"property": "Count", "number": {"does_not_equal": 3}}`, ``, []notion.UUID{"b", "c"}},
+		{"number greater than skips empty", `{"property": "Count", "number": {"greater_than": 0}}`, ``, []notion.UUID{"a", "b"}},
+		{"number less than skips empty", `{"property": "Count", "number": {"less_than": 3}}`, ``, []notion.UUID{"b"}},
+		{"number greater than or equal to", `{"property": "Count", "number": {"greater_than_or_equal_to": 3}}`, ``, []notion.UUID{"a"}},
+		{"number less than or equal to", `{"property": "Count", "number": {"less_than_or_equal_to": 3}}`, ``, []notion.UUID{"a", "b"}},
+		{"number is empty", `{"property": "Count", "number": {"is_empty": true}}`, ``, []notion.UUID{"c"}},
+
+		// checkbox
+		{"checkbox equals true", `{"property": "Done", "checkbox": {"equals": true}}`, ``, []notion.UUID{"a"}},
+		{"unset checkbox is false", `{"property": "Done", "checkbox": {"equals": false}}`, ``, []notion.UUID{"b", "c"}},
+		{"checkbox does not equal", `{"property": "Done", "checkbox": {"does_not_equal": true}}`, ``, []notion.UUID{"b", "c"}},
+
+		// select
+		{"select equals", `{"property": "Tag", "select": {"equals": "Red"}}`, ``, []notion.UUID{"a"}},
+		{"select equals is case-sensitive", `{"property": "Tag", "select": {"equals": "red"}}`, ``, []notion.UUID{}},
+		{"select does not equal matches empty", `{"property": "Tag", "select": {"does_not_equal": "Red"}}`, ``, []notion.UUID{"b", "c"}},
+		{"select is empty", `{"property": "Tag", "select": {"is_empty": true}}`, ``, []notion.UUID{"c"}},
+
+		// multi-select
+		{"multi-select contains", `{"property": "Labels", "multi_select": {"contains": "y"}}`, ``, []notion.UUID{"a", "b"}},
+		{"multi-select does not contain", `{"property": "Labels", "multi_select": {"does_not_contain": "x"}}`, ``, []notion.UUID{"b", "c"}},
+		{"multi-select is not empty", `{"property": "Labels", "multi_select": {"is_not_empty": true}}`, ``, []notion.UUID{"a", "b"}},
+
+		// date
+		{"date equals day", `{"property": "Due", "date": {"equals": "2022-05-11"}}`, ``, []notion.UUID{"b"}},
+		{"date equals time", `{"property": "Due", "date": {"equals": "2022-05-11T15:00:00Z"}}`, ``, []notion.UUID{"b"}},
+		{"date before skips empty", `{"property": "Due", "date": {"before": "2022-05-11"}}`, ``, []notion.UUID{"a"}},
+		{"date after", `{"property": "Due", "date": {"after": "2022-05-10"}}`, ``, []notion.UUID{"b"}},
+		{"date on or before", `{"property": "Due", "date": {"on_or_before": "2022-05-11"}}`, ``, []notion.UUID{"a", "b"}},
+		{"date on or after", `{"property": "Due", "date": {"on_or_after": "2022-05-11T16:00:00Z"}}`, ``, []notion.UUID{}},
+		{"date past week", `{"property": "Due", "date": {"past_week": {}}}`, ``, []notion.UUID{}},
+		{"date is empty", `{"property": "Due", "date": {"is_empty": true}}`, ``, []notion.UUID{"c"}},
+
+		// relation and files
+		{"relation contains without dashes", `{"property": "Rel", "relation": {"contains": "5f3a9c8e1d2b4c6a9e7f0a1b2c3d4e5f"}}`, ``, []notion.UUID{"a"}},
+		{"relation is empty", `{"property": "Rel", "relation": {"is_empty": true}}`, ``, []notion.UUID{"b", "c"}},
+		{"files is not empty", `{"property": "Files", "files": {"is_not_empty": true}}`, ``, []notion.UUID{"a"}},
+
+		// timestamps
+		{"created time", `{"timestamp": "created_time", "created_time": {"equals": "2022-05-01"}}`, ``, []notion.UUID{"a", "b"}},
+		{"last edited time", `{"timestamp": "last_edited_time", "last_edited_time": {"after": "2022-05-01"}}`, ``, []notion.UUID{"a", "b"}},
+
+		// compound
+		{"and", `{"and": [{"property": "Done", "checkbox": {"equals": false}}, {"property": "Count", "number": {"is_not_empty": true}}]}`, ``, []notion.UUID{"b"}},
+		{"or", `{"or": [{"property": "Tag", "select": {"equals": "Red"}}, {"property": "Name", "title": {"contains": "pie"}}]}`, ``, []notion.UUID{"a", "c"}},
+
+		// sorts
+		{"text sorts ignore case", `{}`, `[{"property": "Name", "direction": "descending"}]`, []notion.UUID{"c", "b", "a"}},
+		{"empty numbers come last ascending", `{}`, `[{"property": "Count", "direction": "ascending"}]`, []notion.UUID{"b", "a", "c"}},
+		{"empty numbers come last descending", `{}`, `[{"property": "Count", "direction": "descending"}]`, []notion.UUID{"a", "b", "c"}},
+		{"empty dates come last", `{}`, `[{"property": "Due", "direction": "descending"}]`, []notion.UUID{"b", "a", "c"}},
+		{"empty selects come last", `{}`, `[{"property": "Tag", "direction": "descending"}]`, []notion.UUID{"b", "a", "c"}},
+		{"by timestamp", `{}`, `[{"timestamp": "last_edited_time", "direction": "ascending"}]`, []notion.UUID{"c", "b", "a"}},
+		{"several sorts", `{}`, `[{"property": "Done", "direction": "ascending"}, {"property": "Name", "direction": "descending"}]`, []notion.UUID{"c", "b", "a"}},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := query.Filter{}
+			require.NoError(t, json.Unmarshal([]byte(tt.filter), &f))
+
+			sorts := query.Sorts{}
+			if tt.sorts != "" {
+				require.NoError(t, json.Unmarshal([]byte(tt.sorts), &sorts))
+			}
+
+			assert.Equal(t, tt.want, ids(query.Evaluate(conformancePages(), &f, sorts)))
+		})
+	}
+}
+
+// TestConformance_Unsupported checks that filters that can't be evaluated are rejected
+// instead of matching every page.
+func TestConformance_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name   string
+		filter string
+		err    string
+	}{
+		{"status", `{"property": "Stage", "status": {"equals": "Done"}}`, `filter condition "status" is not supported`},
+		{"people", `{"property": "Owner", "people": {"contains": "a"}}`, `filter condition "people" is not supported`},
+		{"formula", `{"property": "Total", "formula": {"number": {"equals": 1}}}`, `filter condition "formula" is not supported`},
+		{"rollup", `{"property": "Sum", "rollup": {"any": {"number": {"equals": 1}}}}`, `filter condition "rollup" is not supported`},
+		{"unique ID", `{"property": "ID", "unique_id": {"equals": 1}}`, `filter condition "unique_id" is not supported`},
+		{"typo in condition", `{"property": "Name", "titel": {"equals": "apple"}}`, `filter condition "titel" is not supported`},
+		{"typo in operator", `{"property": "Name", "title": {"equal": "apple"}}`, `json: unknown field "equal"`},
+		{"nested", `{"or": [{"property": "Done", "checkbox": {"equals": true}}, {"property": "Stage", "status": {"equals": "Done"}}]}`, `filter condition "status" is not supported`},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := query.Filter{}
+			assert.EqualError(t, json.Unmarshal([]byte(tt.filter), &f), tt.err)
+		})
+	}
+
+	for _, tt := range []struct {
+		name   string
+		filter query.Filter
+		err    string
+	}{
+		{"no condition", query.Filter{Property: "Name"}, `filter of property "Name" has no condition`},
+		{"timestamp without condition", query.Filter{
+			Timestamp: query.TimestampCreatedTime, LastEditedTime: &query.DateCondition{},
+		}, `filter by timestamp "created_time" has no condition for it`},
+		{"nested", query.Filter{And: []query.Filter{{Property: "Name"}}}, `filter of property "Name" has no condition`},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.EqualError(t, tt.filter.Validate(), tt.err)
+			assert.Empty(t, query.Evaluate(conformancePages(), &tt.filter, nil))
+		})
+	}
+}
+
+func TestEvaluateExact(t *testing.T) {
+	t.Parallel()
+
+	pages := []database.Page{}
+
+	for _, tt := range []struct {
+		id    notion.UUID
+		count string
+		total string
+	}{
+		{"a", "16777217", `{"type":"string","string":"beta"}`},
+		{"b", "16777216", `{"type":"string","string":"alpha"}`},
+	} {
+		// a float32 can't hold the first count
+		p := database.Page{Page: newPage(tt.id, "", false, 16777216)}
+		p.Properties["Total"] = notion.PropertyValue{Type: notion.PropertyTypeFormula}
+		p.Numbers = database.Numbers{"Count": json.Number(tt.count)}
+		p.Raw = database.RawValues{"Total": json.RawMessage(tt.total)}
+
+		pages = append(pages, p)
+	}
+
+	exactIDs := func(pages []database.Page) []notion.UUID {
+		ids := []notion.UUID{}
+		for _, p := range pages {
+			ids = append(ids, p.Id)
+		}
+
+		return ids
+	}
+
+	n := 16777217.0
+	f := &query.Filter{Property: "Count", Number: &query.NumberCondition{Equals: &n}}
+
+	assert.Equal(t, []notion.UUID{"a"}, exactIDs(query.EvaluateExact(pages, f, nil)))
+	assert.Equal(t, []notion.UUID{"b", "a"}, exactIDs(query.EvaluateExact(pages, nil, query.Sorts{
+		{Property: "Count", Direction: notion.SortDirectionAscending},
+	})))
+
+	// formulas are sorted by their results
+	assert.Equal(t, []notion.UUID{"b", "a"}, exactIDs(query.EvaluateExact(pages, nil, query.Sorts{
+		{Property: "Total", Direction: notion.SortDirectionAscending},
+	})))
+}
+
+func TestEvaluateExact_Options(t *testing.T) {
+	t.Parallel()
+
+	pages := []database.Page{}
+
+	for _, tt := range []struct {
+		id          notion.UUID
+		size, stage string
+	}{
+		{"a", "L", "Done"},
+		{"b", "S", "Not started"},
+		{"c", "M", "In progress"},
+	} {
+		p := database.Page{Page: newPage(tt.id, "", false, 0)}
+		p.Properties["Size"] = notion.PropertyValue{Select: &notion.SelectValue{Name: tt.size}}
+		p.Properties["Stage"] = notion.PropertyValue{Type: database.PropertyTypeStatus}
+		p.Raw = database.RawValues{"Stage": database.Status(tt.stage)}
+
+		pages = append(pages, p)
+	}
+
+	sortedBy := func(prop string, opts ...query.Option) []notion.UUID {
+		ids := []notion.UUID{}
+		for _, p := range query.EvaluateExact(pages, nil, query.Sorts{
+			{Property: prop, Direction: notion.SortDirectionAscending},
+		}, opts...) {
+			ids = append(ids, p.Id)
+		}
+
+		return ids
+	}
+
+	// without the order of the options, they are sorted by name
+	assert.Equal(t, []notion.UUID{"a", "c", "b"}, sortedBy("Size"))
+	assert.Equal(t, []notion.UUID{"a", "c", "b"}, sortedBy("Stage"))
+
+	assert.Equal(t, []notion.UUID{"b", "c", "a"}, sortedBy("Size", query.WithSchema(notion.PropertyMetaMap{
+		"Size": {Id: "size", Type: notion.PropertyTypeSelect, Select: &notion.PropertyOptionsWrapper{
+			Options: []notion.PropertyOption{{Name: "S"}, {Name: "M"}, {Name: "L"}},
+		}},
+	})))
+
+	assert.Equal(t, []notion.UUID{"b", "c", "a"}, sortedBy("Stage", query.WithStatuses(map[string]database.StatusConfig{
+		"Stage": {Options: []notion.PropertyOption{{Name: "Not started"}, {Name: "In progress"}, {Name: "Done"}}},
+	})))
+}
@@ -0,0 +1,240 @@
+package query
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/faetools/go-notion-codegen/database"
+	"github.com/faetools/go-notion/pkg/notion"
+)
+
+// SortBy is a sort of a database query, encoded the way Notion's API expects it.
+//
+// Unlike notion.Sort, it can sort by a timestamp of the page.
+type SortBy struct {
+	// Property is the name or ID of the property to sort by.
+	Property string `json:"property,omitempty"`
+	// Timestamp is set instead of the property to sort by a timestamp of the page.
+	Timestamp string               `json:"timestamp,omitempty"`
+	Direction notion.SortDirection `json:"direction"`
+}
+
+// Sorts are sorts of a database query.
+// The first sort takes precedence, the others are used if the values are equal.
+type Sorts []SortBy
+
+// NewSorts returns the sorts equivalent to the notion sorts.
+func NewSorts(sorts notion.Sorts) Sorts {
+	res := make(Sorts, len(sorts))
+	for i, s := range sorts {
+		res[i] = SortBy{Property: s.Property, Direction: s.Direction}
+	}
+
+	return res
+}
+
+// Option changes how pages are sorted.
+type Option func(*options)
+
+type options struct {
+	// order holds the names of the options of selects and statuses by the name or ID of their property,
+	// in the order Notion sorts them.
+	order map[string][]string
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{order: map[string][]string{}}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithSchema sorts selects by the order of their options in the schema, as Notion does, instead of by name.
+func WithSchema(props notion.PropertyMetaMap) Option {
+	return func(o *options) {
+		for name, meta := range props {
+			if meta.Select == nil {
+				continue
+			}
+
+			names := make([]string, len(meta.Select.Options))
+			for i, opt := range meta.Select.Options {
+				names[i] = opt.Name
+			}
+
+			o.order[name] = names
+
+			if meta.Id != "" {
+				o.order[meta.Id] = names
+			}
+		}
+	}
+}
+
+// WithStatuses sorts statuses by the order of their options, as Notion does, instead of by name.
+// The statuses are given by property name, as the schema can't hold their options.
+func WithStatuses(statuses map[string]database.StatusConfig) Option {
+	return func(o *options) {
+		for name, status := range statuses {
+			names := make([]string, len(status.Options))
+			for i, opt := range status.Options {
+				names[i] = opt.Name
+			}
+
+			o.order[name] = names
+		}
+	}
+}
+
+// Sort sorts the pages. Pages that are equal keep their order.
+// Selects are sorted by name.
+func (sorts Sorts) Sort(pages notion.Pages) {
+	exact := exactPages(pages)
+	sorts.SortExact(exact)
+	copy(pages, pagesOf(exact))
+}
+
+// SortExact is like Sort, but compares the exact numbers and the raw values of the pages.
+// Selects and statuses are sorted by the order of their options, if the options give it.
+func (sorts Sorts) SortExact(pages []database.Page, opts ...Option) {
+	if len(sorts) == 0 {
+		return
+	}
+
+	o := newOptions(opts)
+
+	sort.SliceStable(pages, func(i, j int) bool {
+		for _, s := range sorts {
+			if c := s.compare(pages[i], pages[j], o); c != 0 {
+				return c < 0
+			}
+		}
+
+		return false
+	})
+}
+
+// compare returns -1 if page a comes before b, 1 if it comes after and 0 if they are equal.
+func (s SortBy) compare(a, b database.Page, o *options) int {
+	var va, vb database.Value
+
+	switch s.Timestamp {
+	case TimestampCreatedTime:
+		va, vb = createdTime(a.Page), createdTime(b.Page)
+	case TimestampLastEditedTime:
+		va = database.Value{PropertyValue: notion.PropertyValue{Date: &notion.Date{Start: a.LastEditedTime}}}
+		vb = database.Value{PropertyValue: notion.PropertyValue{Date: &notion.Date{Start: b.LastEditedTime}}}
+	default:
+		va, vb = property(a, s.Property), property(b, s.Property)
+	}
+
+	// empty values come last, whatever the direction
+	switch ea, eb := isEmpty(va), isEmpty(vb); {
+	case va.Checkbox != nil || vb.Checkbox != nil:
+		// an unchecked checkbox is just false
+	case ea && eb:
+		return 0
+	case ea:
+		return 1
+	case eb:
+		return -1
+	}
+
+	c := compare(va, vb)
+
+	if order, ok := o.order[s.Property]; ok && va.Select != nil && vb.Select != nil {
+		// options not in the order come last
+		if byOrder := compareOrdered(index(order, va.Select.Name), index(order, vb.Select.Name)); byOrder != 0 {
+			c = byOrder
+		}
+	}
+
+	if s.Direction == notion.SortDirectionDescending {
+		return -c
+	}
+
+	return c
+}
+
+// index returns the index of the name in the names, or their number if it is not one of them.
+func index(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+
+	return len(names)
+}
+
+func createdTime(p notion.Page) database.Value {
+	if p.CreatedTime == nil {
+		return database.Value{}
+	}
+
+	return database.Value{PropertyValue: notion.PropertyValue{Date: &notion.Date{Start: *p.CreatedTime}}}
+}
+
+// isEmpty reports whether the property value is empty.
+func isEmpty(v database.Value) bool {
+	switch {
+	case v.Checkbox != nil, number(v) != nil:
+		return false
+	case v.Date != nil:
+		return v.Date.Start.IsZero()
+	case v.Relation != nil:
+		return len(*v.Relation) == 0
+	case v.Files != nil:
+		return len(*v.Files) == 0
+	default:
+		return text(v) == ""
+	}
+}
+
+// compare returns -1 if a is smaller than b, 1 if it is bigger and 0 if they are equal.
+func compare(a, b database.Value) int {
+	switch na, nb := number(a), number(b); {
+	case na != nil && nb != nil:
+		return compareOrdered(*na, *nb)
+	case a.Checkbox != nil || b.Checkbox != nil:
+		return compareBool(a.GetCheckbox(), b.GetCheckbox())
+	case a.Date != nil || b.Date != nil:
+		return compareOrdered(a.GetDate().Start.UnixNano(), b.GetDate().Start.UnixNano())
+	case a.Relation != nil || b.Relation != nil:
+		return compareOrdered(len(a.GetRelation()), len(b.GetRelation()))
+	default:
+		ta, tb := text(a), text(b)
+
+		// texts are sorted alphabetically, regardless of their case
+		if c := compareOrdered(strings.ToLower(ta), strings.ToLower(tb)); c != 0 {
+			return c
+		}
+
+		return compareOrdered(ta, tb)
+	}
+}
+
+func compareOrdered[T ~float64 | ~int | ~int64 | ~string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,290 @@
+// Package transport provides HTTP transports to be used by a notion.Client.
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faetools/client"
+)
+
+const (
+	// Notion allows an average of three requests per second.
+	defaultRate       = 3
+	defaultBurst      = 3
+	defaultMaxRetries = 5
+	defaultBackoff    = 500 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// RateLimiter is an HTTPRequestDoer that keeps to Notion's rate limit
+// and retries requests that failed temporarily.
+//
+// Requests are limited by a token bucket. If Notion still responds with 429 Too Many Requests,
+// all requests are paused for as long as the Retry-After header demands.
+// Requests that Notion could not handle due to a server error are retried if they are idempotent.
+type RateLimiter struct {
+	next client.HTTPRequestDoer
+
+	rate       float64
+	burst      float64
+	maxRetries int
+	backoff    time.Duration
+	maxBackoff time.Duration
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	paused time.Time
+}
+
+// RateLimitOption sets an option of the rate limiter.
+type RateLimitOption func(*RateLimiter)
+
+// Rate sets how many requests per second are sent on average and how many can be sent at once.
+func Rate(perSecond float64, burst int) RateLimitOption {
+	return func(l *RateLimiter) {
+		l.rate = perSecond
+		l.burst = float64(burst)
+	}
+}
+
+// MaxRetries sets how often a request is retried.
+func MaxRetries(n int) RateLimitOption {
+	return func(l *RateLimiter) { l.maxRetries = n }
+}
+
+// Backoff sets how long to wait before retrying at most.
+// The first retry waits up to the base duration, each further retry twice as long as the one before, up to max.
+func Backoff(base, max time.Duration) RateLimitOption {
+	return func(l *RateLimiter) {
+		l.backoff = base
+		l.maxBackoff = max
+	}
+}
+
+// NewRateLimiter returns a rate limiter that sends requests with the next doer.
+func NewRateLimiter(next client.HTTPRequestDoer, opts ...RateLimitOption) *RateLimiter {
+	l := &RateLimiter{
+		next:       next,
+		rate:       defaultRate,
+		burst:      defaultBurst,
+		maxRetries: defaultMaxRetries,
+		backoff:    defaultBackoff,
+		maxBackoff: defaultMaxBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	l.tokens = l.burst
+
+	return l
+}
+
+// WithRateLimit returns a client option that wraps the doer of the client in a rate limiter.
+// Add it after any option that sets the doer.
+func WithRateLimit(opts ...RateLimitOption) client.Option {
+	return func(c *client.Client) error {
+		next := c.Client
+		if next == nil {
+			next = &http.Client{}
+		}
+
+		c.Client = NewRateLimiter(next, opts...)
+
+		return nil
+	}
+}
+
+// Do sends the request once the rate limit allows it, retrying it if needed.
+func (l *RateLimiter) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		if err := l.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, err := l.next.Do(req)
+
+		retry, wait := l.shouldRetry(req, resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+
+		// we are going to send the request again
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// shouldRetry reports whether the request should be sent again and how long to wait before doing so.
+func (l *RateLimiter) shouldRetry(req *http.Request, resp *http.Response, err error, attempt int,
+) (bool, time.Duration) {
+	if attempt >= l.maxRetries || !rewindable(req) {
+		return false, 0
+	}
+
+	switch {
+	case err != nil:
+		// the request might have reached Notion
+		return idempotent(req) && req.Context().Err() == nil, l.jitter(attempt)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		// Notion did not handle the request, so it is safe to send it again
+		if d, ok := retryAfter(resp); ok {
+			l.pause(d)
+			return true, 0
+		}
+
+		return true, l.jitter(attempt)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return idempotent(req), l.jitter(attempt)
+	default:
+		return false, 0
+	}
+}
+
+// jitter returns a random duration up to the exponential backoff of the attempt.
+func (l *RateLimiter) jitter(attempt int) time.Duration {
+	d := l.backoff << attempt
+	if d <= 0 || d > l.maxBackoff {
+		d = l.maxBackoff
+	}
+
+	if d <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(d))) //nolint:gosec // jitter does not need to be secure
+}
+
+// pause pauses all requests for the given duration.
+func (l *RateLimiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(l.paused) {
+		l.paused = until
+	}
+}
+
+// wait waits until the next request may be sent.
+func (l *RateLimiter) wait(ctx context.Context) error {
+	return sleep(ctx, l.reserve())
+}
+
+// reserve takes a token from the bucket and returns how long to wait until it is available.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+
+	l.last = now
+	l.tokens--
+
+	wait := l.paused.Sub(now)
+
+	if l.tokens < 0 && l.rate > 0 {
+		if d := time.Duration(-l.tokens / l.rate * float64(time.Second)); d > wait {
+			wait = d
+		}
+	}
+
+	return wait
+}
+
+// idempotent reports whether sending the request several times has the same effect as sending it once.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		// querying and searching only reads data
+		return strings.HasSuffix(req.URL.Path, "/query") || strings.HasSuffix(req.URL.Path, "/search")
+	default:
+		return false
+	}
+}
+
+// retryAfter returns the duration of the Retry-After header.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	h := resp.Header.Get("Retry-After")
+	if h == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(h); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(h); err == nil {
+		return time.Until(t), true
+	}
+
+	return 0, false
+}
+
+// rewindable reports whether the request can be sent again.
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns a copy of the request that can be sent again.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("getting body of %s %s: %w", req.Method, req.URL, err)
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = body
+
+	return req, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,125 @@
+package transport_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/faetools/go-notion-codegen/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newServer returns a server that responds with the given status codes, one after the other.
+func newServer(t *testing.T, codes ...int) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var calls int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+
+		b, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		if r.Method != http.MethodGet {
+			assert.Equal(t, "body", string(b))
+		}
+
+		code := http.StatusOK
+		if int(n) <= len(codes) {
+			code = codes[n-1]
+		}
+
+		if code == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "0")
+		}
+
+		w.WriteHeader(code)
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return srv, &calls
+}
+
+func newRequest(t *testing.T, method, url string) *http.Request {
+	t.Helper()
+
+	var body io.Reader
+	if method != http.MethodGet {
+		body = strings.NewReader("body")
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	require.NoError(t, err)
+
+	return req
+}
+
+func TestRateLimiter_Retries(t *testing.T) {
+	t.Parallel()
+
+	backoff := transport.Backoff(time.Millisecond, 10*time.Millisecond)
+
+	for _, tt := range []struct {
+		name   string
+		method string
+		path   string
+		codes  []int
+		status int
+		calls  int32
+	}{
+		{"too many requests", http.MethodPatch, "/v1/pages/id",
+			[]int{http.StatusTooManyRequests, http.StatusTooManyRequests}, http.StatusOK, 3},
+		{"server error when reading", http.MethodGet, "/v1/pages/id",
+			[]int{http.StatusBadGateway, http.StatusServiceUnavailable}, http.StatusOK, 3},
+		{"server error when querying", http.MethodPost, "/v1/databases/id/query",
+			[]int{http.StatusInternalServerError}, http.StatusOK, 2},
+		{"server error when writing", http.MethodPatch, "/v1/pages/id",
+			[]int{http.StatusInternalServerError}, http.StatusInternalServerError, 1},
+		{"bad request", http.MethodGet, "/v1/pages/id",
+			[]int{http.StatusBadRequest}, http.StatusBadRequest, 1},
+		{"giving up", http.MethodGet, "/v1/pages/id",
+			[]int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}, http.StatusBadGateway, 3},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv, calls := newServer(t, tt.codes...)
+			l := transport.NewRateLimiter(srv.Client(), backoff, transport.MaxRetries(2))
+
+			resp, err := l.Do(newRequest(t, tt.method, srv.URL+tt.path))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, tt.calls, atomic.LoadInt32(calls))
+		})
+	}
+}
+
+func TestRateLimiter_Rate(t *testing.T) {
+	t.Parallel()
+
+	srv, calls := newServer(t)
+	l := transport.NewRateLimiter(srv.Client(), transport.Rate(50, 2))
+
+	start := time.Now()
+
+	for i := 0; i < 5; i++ {
+		resp, err := l.Do(newRequest(t, http.MethodGet, srv.URL))
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// two requests can be sent at once, the other three need to wait 20ms each
+	assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+	assert.Equal(t, int32(5), atomic.LoadInt32(calls))
+}
@@ -0,0 +1,350 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/faetools/client"
+	"github.com/spf13/afero"
+)
+
+const (
+	redacted = "REDACTED"
+
+	startCursor = "start_cursor"
+	nextCursor  = "next_cursor"
+)
+
+// Cassette holds recorded interactions with the Notion API.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and the response to it.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a recorded request.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	// Body is the canonicalized JSON body.
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// RecordedResponse is a recorded response.
+type RecordedResponse struct {
+	Status int             `json:"status"`
+	Header http.Header     `json:"header,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// key returns what identifies the request.
+func (r RecordedRequest) key() string {
+	// the body might have been indented when the cassette was saved
+	body := &bytes.Buffer{}
+	if err := json.Compact(body, r.Body); err != nil {
+		body.Write(r.Body)
+	}
+
+	return r.Method + " " + r.Path + "?" + r.Query + " " + body.String()
+}
+
+// cursors replaces cursors with placeholders so that recordings don't depend on them.
+type cursors struct {
+	mu   sync.Mutex
+	byID map[string]string
+}
+
+func (c *cursors) placeholder(cursor string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.byID == nil {
+		c.byID = map[string]string{}
+	}
+
+	if p, ok := c.byID[cursor]; ok {
+		return p
+	}
+
+	p := "cursor-" + strconv.Itoa(len(c.byID)+1)
+	c.byID[cursor] = p
+
+	return p
+}
+
+// Recorder is an http.RoundTripper that records all interactions.
+//
+// The bearer token is redacted and cursors are replaced with placeholders.
+type Recorder struct {
+	next http.RoundTripper
+	fs   afero.Fs
+	path string
+
+	cursors cursors
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder returns a recorder that sends requests with the next round tripper
+// and saves the cassette to the given path.
+// If next is nil, http.DefaultTransport is used.
+func NewRecorder(fs afero.Fs, path string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &Recorder{next: next, fs: fs, path: path}
+}
+
+// WithRecorder returns a client option that records all interactions of the client.
+// Add it before any option that wraps the doer of the client, e.g. WithRateLimit.
+func WithRecorder(r *Recorder) client.Option {
+	return func(c *client.Client) error {
+		c.Client = &http.Client{Transport: r}
+		return nil
+	}
+}
+
+// RoundTrip sends the request and records it together with the response.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading body of %s %s: %w", req.Method, req.URL, err)
+	}
+
+	recReq, err := recordRequest(req, body, r.cursors.placeholder)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response to %s %s: %w", req.Method, req.URL, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: recReq,
+		Response: RecordedResponse{
+			Status: resp.StatusCode,
+			Header: http.Header{"Content-Type": resp.Header.Values("Content-Type")},
+			Body:   recordResponse(respBody, r.cursors.placeholder),
+		},
+	})
+
+	return resp, nil
+}
+
+// Save saves the cassette as JSON.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding cassette: %w", err)
+	}
+
+	if err := afero.WriteFile(r.fs, r.path, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("saving cassette: %w", err)
+	}
+
+	return nil
+}
+
+// recordRequest returns the recorded request, with the bearer token redacted
+// and the cursor replaced.
+func recordRequest(req *http.Request, body []byte, replace func(string) string) (RecordedRequest, error) {
+	q := req.URL.Query()
+	if cursor := q.Get(startCursor); cursor != "" {
+		q.Set(startCursor, replace(cursor))
+	}
+
+	canonical, err := canonicalize(body, startCursor, replace)
+	if err != nil {
+		return RecordedRequest{}, fmt.Errorf("canonicalizing body of %s %s: %w", req.Method, req.URL, err)
+	}
+
+	header := http.Header{}
+
+	for k, v := range req.Header {
+		if k == "Authorization" {
+			v = []string{"Bearer " + redacted}
+		}
+
+		header[k] = v
+	}
+
+	return RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  q.Encode(),
+		Header: header,
+		Body:   canonical,
+	}, nil
+}
+
+// recordResponse returns the response body with the cursor replaced.
+// Bodies that are not JSON are recorded as JSON strings.
+func recordResponse(body []byte, replace func(string) string) json.RawMessage {
+	canonical, err := canonicalize(body, nextCursor, replace)
+	if err == nil {
+		return canonical
+	}
+
+	s, _ := json.Marshal(string(body))
+
+	return s
+}
+
+// canonicalize returns the JSON with sorted keys and the cursor with the given key replaced.
+func canonicalize(body []byte, key string, replace func(string) string) (json.RawMessage, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if m, ok := v.(map[string]interface{}); ok {
+		if cursor, ok := m[key].(string); ok && cursor != "" {
+			m[key] = replace(cursor)
+		}
+	}
+
+	return json.Marshal(v)
+}
+
+// Replayer is an http.RoundTripper that responds with recorded interactions.
+//
+// Requests are matched by their method, path, query and canonicalized JSON body.
+// Each interaction is replayed once, in the order they were recorded.
+type Replayer struct {
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// NewReplayer returns a replayer of the cassette saved at the given path.
+func NewReplayer(fs afero.Fs, path string) (*Replayer, error) {
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("reading cassette: %w", err)
+	}
+
+	r := &Replayer{}
+	if err := json.Unmarshal(b, &r.cassette); err != nil {
+		return nil, fmt.Errorf("decoding cassette %s: %w", path, err)
+	}
+
+	r.used = make([]bool, len(r.cassette.Interactions))
+
+	return r, nil
+}
+
+// WithReplayer returns a client option that makes the client respond with recorded interactions.
+// Add it before any option that wraps the doer of the client, e.g. WithRateLimit.
+func WithReplayer(r *Replayer) client.Option {
+	return func(c *client.Client) error {
+		c.Client = &http.Client{Transport: r}
+		return nil
+	}
+}
+
+// RoundTrip responds with the first recorded interaction that matches the request and was not yet replayed.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading body of %s %s: %w", req.Method, req.URL, err)
+	}
+
+	// cursors are already replaced in replayed responses
+	recReq, err := recordRequest(req, body, func(cursor string) string { return cursor })
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || in.Request.key() != recReq.key() {
+			continue
+		}
+
+		r.used[i] = true
+
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+			StatusCode: in.Response.Status,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     in.Response.Header.Clone(),
+			Body:       io.NopCloser(bytes.NewReader(in.Response.Body)),
+			Request:    req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, (&url.URL{
+		Path:     recReq.Path,
+		RawQuery: recReq.Query,
+	}).String())
+}
+
+// Unused returns the recorded requests that were not replayed.
+func (r *Replayer) Unused() []RecordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reqs := []RecordedRequest{}
+
+	for i, in := range r.cassette.Interactions {
+		if !r.used[i] {
+			reqs = append(reqs, in.Request)
+		}
+	}
+
+	return reqs
+}
+
+// readBody reads the body and replaces it with one that can be read again.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	b, err := io.ReadAll(*body)
+	(*body).Close()
+
+	*body = io.NopCloser(bytes.NewReader(b))
+
+	return b, err
+}
@@ -0,0 +1,66 @@
+package transport_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/faetools/go-notion-codegen/notiontest"
+	"github.com/faetools/go-notion-codegen/transport"
+	"github.com/faetools/go-notion/pkg/notion"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dbID = "4a5b6c7d-0000-4000-8000-000000000001"
+
+func TestRecordReplay(t *testing.T) {
+	t.Parallel()
+
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	srv.AddDatabase(dbID, "Tasks", notion.PropertyMetaMap{"Name": notion.TitleProperty})
+	require.NoError(t, srv.AddPages(dbID, make(notion.Pages, 150)...))
+
+	fs := afero.NewMemMapFs()
+	ctx := context.Background()
+
+	// record
+	rec := transport.NewRecorder(fs, "cassette.json", nil)
+
+	cli, err := srv.Client(transport.WithRecorder(rec))
+	require.NoError(t, err)
+
+	recorded, err := cli.GetAllDatabaseEntries(ctx, dbID)
+	require.NoError(t, err)
+	require.Len(t, recorded, 150)
+
+	require.NoError(t, rec.Save())
+
+	b, err := afero.ReadFile(fs, "cassette.json")
+	require.NoError(t, err)
+
+	cassette := string(b)
+	assert.NotContains(t, cassette, "secret")
+	assert.Contains(t, cassette, `"Bearer REDACTED"`)
+	assert.Contains(t, cassette, `"next_cursor": "cursor-1"`)
+	assert.Contains(t, cassette, `"start_cursor": "cursor-1"`)
+	assert.Contains(t, cassette, "\n  \"interactions\": [\n")
+
+	// replay without the server
+	rep, err := transport.NewReplayer(fs, "cassette.json")
+	require.NoError(t, err)
+
+	cli, err = notion.NewDefaultClient("other secret", transport.WithReplayer(rep))
+	require.NoError(t, err)
+
+	replayed, err := cli.GetAllDatabaseEntries(ctx, dbID)
+	require.NoError(t, err)
+	assert.Equal(t, recorded, replayed)
+	assert.Empty(t, rep.Unused())
+
+	// every interaction is replayed once
+	_, err = cli.GetAllDatabaseEntries(ctx, dbID)
+	assert.ErrorContains(t, err, "no recorded interaction for POST /v1/databases/"+dbID+"/query")
+}